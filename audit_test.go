@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditLogRecordsMutations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.AuditLog = path
+	})
+
+	w := serve(router, http.MethodPost, "/recipes", newRecipeBody, "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusCreated)
+	id := decodeBody[Recipe](t, w).ID
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/"+id,
+		`{"name":"Cheese toast","ingredients":["bread","cheese"],"instructions":["Toast."]}`), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/"+id, "", "X-API-KEY", "bob-key"), http.StatusOK)
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []AuditEntry
+	for s := bufio.NewScanner(f); s.Scan(); {
+		var e AuditEntry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", s.Text(), err)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("entry %+v has no time", e)
+		}
+		got = append(got, e)
+	}
+	want := []AuditEntry{
+		{Action: "create", RecipeID: id, User: "alice"},
+		{Action: "update", RecipeID: id},
+		{Action: "delete", RecipeID: id, User: "bob"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("audit log has %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Action != want[i].Action || got[i].RecipeID != want[i].RecipeID || got[i].User != want[i].User {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAuditLogFailureIsNotFatal(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.AuditLog = filepath.Join(t.TempDir(), "missing", "audit.log")
+	})
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", newRecipeBody), http.StatusCreated)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userContextKey is the gin context key holding the authenticated user.
+const userContextKey = "user"
+
+// AuthMiddleware identifies the caller from the X-API-KEY header. Requests
+// without a key continue anonymously; requests with an unknown key are
+// rejected.
+func AuthMiddleware(keys map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-KEY")
+		if key == "" {
+			c.Next()
+			return
+		}
+		user, ok := keys[key]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key not provided or invalid"})
+			return
+		}
+		c.Set(userContextKey, user)
+		c.Next()
+	}
+}
+
+// RequireAuth rejects anonymous requests. It must run after AuthMiddleware.
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := currentUser(c); !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key not provided or invalid"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// currentUser returns the authenticated user, if any.
+func currentUser(c *gin.Context) (string, bool) {
+	user := c.GetString(userContextKey)
+	return user, user != ""
+}
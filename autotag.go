@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// autoTagRule adds tags to recipes with an ingredient containing phrase, a
+// run of whole folded words such as "soy sauce".
+type autoTagRule struct {
+	phrase string
+	tags   []string
+}
+
+// autoTagger derives tags from ingredients on create. No rules means
+// automatic tagging is off.
+type autoTagger struct {
+	rules []autoTagRule
+}
+
+var autoTags autoTagger
+
+// loadAutoTags enables automatic tagging if cfg asks for it, reading the
+// rules file: a JSON object mapping an ingredient word or phrase to the
+// tags it implies, e.g. {"chicken": ["chicken", "poultry"]}.
+func loadAutoTags(cfg AutoTagConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	data, err := os.ReadFile(cfg.RulesFile)
+	if err != nil {
+		return err
+	}
+	var mapping map[string][]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return fmt.Errorf("%s: %w", cfg.RulesFile, err)
+	}
+	var t autoTagger
+	for phrase, tags := range mapping {
+		words := textTokens(phrase)
+		if len(words) == 0 {
+			return fmt.Errorf("%s: %q has no words to match", cfg.RulesFile, phrase)
+		}
+		t.rules = append(t.rules, autoTagRule{phrase: " " + strings.Join(words, " ") + " ", tags: normalizeTags(tags)})
+	}
+	if len(t.rules) == 0 {
+		return fmt.Errorf("auto-tag rules %s are empty", cfg.RulesFile)
+	}
+	autoTags = t
+	return nil
+}
+
+// derive returns the tags implied by r's ingredients, possibly with
+// duplicates; normalizeRecipe removes them once merged into r.Tags.
+func (t autoTagger) derive(r Recipe) []string {
+	var out []string
+	for _, line := range r.Ingredients {
+		words := " " + strings.Join(textTokens(line), " ") + " "
+		for _, rule := range t.rules {
+			if strings.Contains(words, rule.phrase) {
+				out = append(out, rule.tags...)
+			}
+		}
+	}
+	return out
+}
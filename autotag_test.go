@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+const autoTagRules = `{"chicken": ["Chicken", "poultry"], "soy sauce": ["asian"], "tofu": ["vegan"]}`
+
+// autoTaggedRouter is a router with automatic tagging on using
+// autoTagRules.
+func autoTaggedRouter(t *testing.T) http.Handler {
+	t.Helper()
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	if err := loadAutoTags(AutoTagConfig{Enabled: true, RulesFile: writeFixture(t, autoTagRules)}); err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+func TestAutoTagsMergedOnCreate(t *testing.T) {
+	router := autoTaggedRouter(t)
+
+	body := `{"name":"Teriyaki","tags":["Poultry","dinner"],"ingredients":["2 chicken thighs","1 tbsp soy sauce","200 g chickpeas","1 tbsp soy"],"instructions":["Glaze."]}`
+	w := serve(router, http.MethodPost, "/recipes", body)
+	expectStatus(t, w, http.StatusCreated)
+	if got, want := decodeBody[Recipe](t, w).Tags, []string{"poultry", "dinner", "chicken", "asian"}; !slices.Equal(got, want) {
+		t.Errorf("tags = %q, want %q", got, want)
+	}
+
+	w = serve(router, http.MethodPost, "/recipes/batch", `[{"name":"Mapo tofu","ingredients":["300 g Tofu"],"instructions":["Simmer."]}]`)
+	expectStatus(t, w, http.StatusCreated)
+	id := decodeBody[map[string][]BatchCreateResult](t, w)["results"][0].ID
+	if got := storedRecipe(t, id).Tags; !slices.Equal(got, []string{"vegan"}) {
+		t.Errorf("batch-created tags = %q, want [vegan]", got)
+	}
+
+	w = serve(router, http.MethodPut, "/recipe/"+xidScheme.generate(), `{"name":"Stir fry","ingredients":["tofu"],"instructions":["Fry."]}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[Recipe](t, w).Tags; !slices.Equal(got, []string{"vegan"}) {
+		t.Errorf("upserted tags = %q, want [vegan]", got)
+	}
+}
+
+func TestAutoTagsOnlyOnCreate(t *testing.T) {
+	router := autoTaggedRouter(t)
+
+	w := serve(router, http.MethodPut, "/recipe/r1", `{"name":"Soup","ingredients":["chicken stock"],"instructions":["Heat."]}`)
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[Recipe](t, w).Tags; len(got) != 0 {
+		t.Errorf("update added tags %q", got)
+	}
+}
+
+func TestAutoTagsOff(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes", `{"name":"Roast","ingredients":["1 chicken"],"instructions":["Roast."]}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[Recipe](t, w).Tags; len(got) != 0 {
+		t.Errorf("tags = %q with automatic tagging off", got)
+	}
+	for _, rules := range []string{`{}`, `{"  ": ["x"]}`, `not json`} {
+		if err := loadAutoTags(AutoTagConfig{Enabled: true, RulesFile: writeFixture(t, rules)}); err == nil {
+			t.Errorf("rules %s were accepted", rules)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBackfillSpan is how far back synthetic timestamps reach when the
+// request does not give a range.
+const defaultBackfillSpan = 365 * 24 * time.Hour
+
+// BackfillRequest is the optional body of POST /admin/backfill-timestamps.
+// To defaults to now and From to a year before To.
+type BackfillRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// BackfillResponse reports the recipes given synthetic timestamps.
+type BackfillResponse struct {
+	Changed int      `json:"changed"`
+	IDs     []string `json:"ids"`
+}
+
+// needsBackfill returns the indexes, in store order, of recipes whose
+// PublishedAt is zero or shared with another recipe. Callers must hold
+// recipesMu.
+func needsBackfill() []int {
+	counts := make(map[time.Time]int, len(recipes))
+	for _, r := range recipes {
+		counts[r.PublishedAt.UTC()]++
+	}
+	var out []int
+	for i, r := range recipes {
+		if r.PublishedAt.IsZero() || counts[r.PublishedAt.UTC()] > 1 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// backfillTimestamps gives each recipe in targets a distinct PublishedAt,
+// evenly spaced across [from, to] in store order and clear of every
+// timestamp it keeps. UpdatedAt is raised to match where it would otherwise
+// be earlier. Callers must hold recipesMu for writing.
+func backfillTimestamps(targets []int, from, to time.Time) []string {
+	taken := make(map[time.Time]bool, len(recipes))
+	affected := make(map[int]bool, len(targets))
+	for _, i := range targets {
+		affected[i] = true
+	}
+	for i, r := range recipes {
+		if !affected[i] {
+			taken[r.PublishedAt.UTC()] = true
+		}
+	}
+
+	ids := make([]string, 0, len(targets))
+	step := time.Duration(0)
+	if len(targets) > 1 {
+		step = to.Sub(from) / time.Duration(len(targets)-1)
+	}
+	for n, i := range targets {
+		t := from.Add(step * time.Duration(n)).UTC()
+		for taken[t] {
+			t = t.Add(time.Nanosecond)
+		}
+		taken[t] = true
+		r := recipes[i]
+		r.PublishedAt = Timestamp{t}
+		if r.UpdatedAt.Before(t) {
+			r.UpdatedAt = Timestamp{t}
+		}
+		replaceRecipe(i, r)
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// BackfillTimestampsHandler repairs publication times after a legacy
+// import: recipes with a zero or duplicated publishedAt get distinct
+// synthetic ones spread over the requested range, so the default sort
+// orders them sensibly.
+//
+// @Summary Backfill publication timestamps
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body BackfillRequest false "Range for the synthetic timestamps (default: the past year)"
+// @Success 200 {object} BackfillResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /admin/backfill-timestamps [post]
+func BackfillTimestampsHandler(c *gin.Context) {
+	var req BackfillRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultBackfillSpan)
+	}
+	if !req.From.Before(req.To) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("from (%s) must be before to (%s)", req.From.Format(time.RFC3339), req.To.Format(time.RFC3339))})
+		return
+	}
+
+	recipesMu.Lock()
+	ids := backfillTimestamps(needsBackfill(), req.From, req.To)
+	recipesMu.Unlock()
+
+	for _, id := range ids {
+		auditor.record(c, "update", id)
+	}
+	c.JSON(http.StatusOK, BackfillResponse{Changed: len(ids), IDs: ids})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBackfillTimestamps(t *testing.T) {
+	legacy := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	kept := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	dated := func(id string, at time.Time) Recipe {
+		r := testRecipe(id, id)
+		r.PublishedAt = Timestamp{at}
+		return r
+	}
+	router := newTestRouter(t, withUsers,
+		dated("a", time.Time{}),
+		dated("b", time.Time{}),
+		dated("c", time.Time{}),
+		dated("d", legacy),
+		dated("e", legacy),
+		dated("kept", kept),
+	)
+	auth := []string{"X-API-KEY", "alice-key"}
+	body := `{"from":"2023-01-01T00:00:00Z","to":"2023-12-31T00:00:00Z"}`
+
+	expectStatus(t, serve(router, http.MethodPost, "/admin/backfill-timestamps", body), http.StatusUnauthorized)
+
+	w := serve(router, http.MethodPost, "/admin/backfill-timestamps", body, auth...)
+	expectStatus(t, w, http.StatusOK)
+	resp := decodeBody[BackfillResponse](t, w)
+	if want := []string{"a", "b", "c", "d", "e"}; resp.Changed != 5 || !slices.Equal(resp.IDs, want) {
+		t.Errorf("got %+v, want %q changed", resp, want)
+	}
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	seen := make(map[time.Time]string)
+	for _, id := range []string{"a", "b", "c", "d", "e", "kept"} {
+		r := storedRecipe(t, id)
+		at := r.PublishedAt.Time
+		if prev, dup := seen[at]; dup {
+			t.Errorf("%s and %s share publishedAt %v", prev, id, at)
+		}
+		seen[at] = id
+		if id != "kept" && (at.Before(from) || at.After(to)) {
+			t.Errorf("%s publishedAt %v is outside the range", id, at)
+		}
+		if id != "kept" && r.UpdatedAt.Before(at) {
+			t.Errorf("%s updatedAt %v is before publishedAt %v", id, r.UpdatedAt, at)
+		}
+	}
+	if got := storedRecipe(t, "kept").PublishedAt.Time; !got.Equal(kept) {
+		t.Errorf("kept publishedAt changed to %v", got)
+	}
+
+	w = serve(router, http.MethodPost, "/admin/backfill-timestamps", "", auth...)
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[BackfillResponse](t, w).Changed; got != 0 {
+		t.Errorf("second backfill changed %d recipes, want 0", got)
+	}
+
+	bad := `{"from":"2023-12-31T00:00:00Z","to":"2023-01-01T00:00:00Z"}`
+	expectStatus(t, serve(router, http.MethodPost, "/admin/backfill-timestamps", bad, auth...), http.StatusUnprocessableEntity)
+}
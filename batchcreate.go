@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchCreateResult reports the outcome for one item of a batch create:
+// "created" with the new ID, or "invalid" or "rejected" with an error.
+type BatchCreateResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchCreateRecipesHandler creates every recipe in the JSON array body. By
+// default the batch is all-or-nothing: one invalid item, or too little room
+// in the store, fails it with nothing created. With ?partial=true the valid
+// items are created and each item's outcome is reported instead.
+//
+// @Summary Create several recipes
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param recipes body []Recipe true "Recipes to create"
+// @Param partial query bool false "Create the valid items and report each one instead of failing the batch"
+// @Param noDefaultTags query bool false "Skip the configured default tags"
+// @Success 200 {object} map[string][]BatchCreateResult "Partial mode"
+// @Success 201 {object} map[string][]BatchCreateResult "All created"
+// @Failure 400 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse
+// @Router /recipes/batch [post]
+func BatchCreateRecipesHandler(c *gin.Context) {
+	if !hasBody(c.Request) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errEmptyBody.Error()})
+		return
+	}
+	var list []Recipe
+	if err := bindJSON(c, &list); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if len(list) > maxBatchIDs {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("at most %d recipes per request", maxBatchIDs)})
+		return
+	}
+	partial := c.Query("partial") == "true"
+
+	results := make([]BatchCreateResult, len(list))
+	valid := 0
+	for i := range list {
+		r := &list[i]
+		if c.Query("noDefaultTags") != "true" {
+			r.Tags = append(r.Tags, config.DefaultTags...)
+		}
+		r.Tags = append(r.Tags, autoTags.derive(*r)...)
+		normalizeRecipe(r)
+		if err := validateRecipe(r); err != nil {
+			if !partial {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("recipe %d: %v", i, err)})
+				return
+			}
+			results[i] = BatchCreateResult{Index: i, Status: "invalid", Error: err.Error()}
+			continue
+		}
+		valid++
+	}
+
+	now := time.Now()
+	var evicted, created []string
+	recipesMu.Lock()
+	if !partial && !config.Capacity.fits(valid) {
+		recipesMu.Unlock()
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)})
+		return
+	}
+	for i := range list {
+		if results[i].Status == "invalid" {
+			continue
+		}
+		out, ok := config.Capacity.makeRoom()
+		if !ok {
+			results[i] = BatchCreateResult{Index: i, Status: "rejected", Error: fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)}
+			continue
+		}
+		if out != "" {
+			evicted = append(evicted, out)
+		}
+		r := list[i]
+		r.Thumbnail = ""
+		r.Pinned = false
+		r.ParentID = ""
+		r.ID = newRecipeID()
+		r.PublishedAt = Timestamp{now}
+		r.UpdatedAt = Timestamp{now}
+		insertRecipe(r)
+		created = append(created, r.ID)
+		results[i] = BatchCreateResult{Index: i, Status: "created", ID: r.ID}
+	}
+	recipesMu.Unlock()
+
+	for _, id := range evicted {
+		auditor.record(c, "evict", id)
+	}
+	for _, id := range created {
+		auditor.record(c, "create", id)
+	}
+	status := http.StatusCreated
+	if partial {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"results": results})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+const mixedBatch = `[` + newRecipeBody + `,{"name":"","ingredients":["x"],"instructions":["y"]},` +
+	`{"name":"Jam","category":"snackish","ingredients":["fruit"],"instructions":["Boil."]},` + newRecipeBody + `]`
+
+func TestBatchCreateStrictFailsWhole(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes/batch", mixedBatch)
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+	if n := len(recipes); n != 0 {
+		t.Errorf("stored %d recipes after a failed batch, want 0", n)
+	}
+}
+
+func TestBatchCreatePartial(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes/batch?partial=true", mixedBatch)
+	expectStatus(t, w, http.StatusOK)
+	results := decodeBody[map[string][]BatchCreateResult](t, w)["results"]
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for i, want := range []string{"created", "invalid", "invalid", "created"} {
+		r := results[i]
+		if r.Index != i || r.Status != want {
+			t.Errorf("result %d = %+v, want index %d %s", i, r, i, want)
+		}
+		if want == "created" {
+			if r.Error != "" {
+				t.Errorf("result %d has error %q", i, r.Error)
+			}
+			storedRecipe(t, r.ID)
+		} else if r.ID != "" || r.Error == "" {
+			t.Errorf("result %d = %+v, want an error and no ID", i, r)
+		}
+	}
+	if n := len(recipes); n != 2 {
+		t.Errorf("stored %d recipes, want 2", n)
+	}
+}
+
+func TestBatchCreatePartialOverCapacity(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.Capacity = capacityPolicy{max: 1}
+	})
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/batch", "["+newRecipeBody+","+newRecipeBody+"]"), http.StatusInsufficientStorage)
+
+	w := serve(router, http.MethodPost, "/recipes/batch?partial=true", "["+newRecipeBody+","+newRecipeBody+"]")
+	expectStatus(t, w, http.StatusOK)
+	results := decodeBody[map[string][]BatchCreateResult](t, w)["results"]
+	if results[0].Status != "created" || results[1].Status != "rejected" {
+		t.Errorf("results = %+v, want created then rejected", results)
+	}
+}
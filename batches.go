@@ -0,0 +1,174 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/xid"
+)
+
+// CookBatch is one cooked batch of a recipe and the portions still left.
+type CookBatch struct {
+	ID           string    `json:"id"`
+	RecipeID     string    `json:"recipeId"`
+	RecipeName   string    `json:"recipeName"`
+	ServingsMade int       `json:"servingsMade"`
+	Remaining    int       `json:"remaining"`
+	CookedAt     time.Time `json:"cookedAt"`
+}
+
+// CookBatchRequest is the body of POST /recipe/:id/cook-batch. Servings
+// defaults to the recipe's own servings.
+type CookBatchRequest struct {
+	Servings int `json:"servings"`
+}
+
+// ConsumeRequest is the body of POST /batches/:id/consume. Portions
+// defaults to 1.
+type ConsumeRequest struct {
+	Portions int `json:"portions"`
+}
+
+// batchStore is side data kept apart from the recipes: deleting a recipe
+// does not throw away food that has already been cooked.
+type batchStore struct {
+	mu      sync.Mutex
+	batches map[string]*CookBatch
+}
+
+var cookBatches = &batchStore{batches: make(map[string]*CookBatch)}
+
+func (s *batchStore) add(b CookBatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches[b.ID] = &b
+}
+
+// active returns the batches with portions left, oldest first.
+func (s *batchStore) active() []CookBatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]CookBatch, 0, len(s.batches))
+	for _, b := range s.batches {
+		if b.Remaining > 0 {
+			out = append(out, *b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CookedAt.Before(out[j].CookedAt) })
+	return out
+}
+
+// bindOptionalJSON decodes the body into v if there is one; an empty body
+// leaves v at its zero value.
+func bindOptionalJSON(c *gin.Context, v any) error {
+	if !hasBody(c.Request) {
+		return nil
+	}
+	return bindJSON(c, v)
+}
+
+// CookBatchHandler records that a recipe has been cooked.
+//
+// @Summary Cook a batch of a recipe
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param request body CookBatchRequest false "Servings made (default: the recipe's servings)"
+// @Success 201 {object} CookBatch
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /recipe/{id}/cook-batch [post]
+func CookBatchHandler(c *gin.Context) {
+	var req CookBatchRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	var recipe Recipe
+	if i >= 0 {
+		recipe = recipes[i]
+	}
+	recipesMu.RUnlock()
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+
+	servings := req.Servings
+	if servings == 0 {
+		servings = recipe.Servings
+	}
+	if servings <= 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "servings must be a positive integer; the recipe has no servings to default to"})
+		return
+	}
+	b := CookBatch{
+		ID:           xid.New().String(),
+		RecipeID:     recipe.ID,
+		RecipeName:   recipe.Name,
+		ServingsMade: servings,
+		Remaining:    servings,
+		CookedAt:     time.Now(),
+	}
+	cookBatches.add(b)
+	c.JSON(http.StatusCreated, b)
+}
+
+// ConsumeBatchHandler takes portions from a batch. A batch with nothing
+// left no longer appears in GET /batches.
+//
+// @Summary Consume portions of a batch
+// @Tags batches
+// @Accept json
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Param request body ConsumeRequest false "Portions eaten (default 1)"
+// @Success 200 {object} CookBatch
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /batches/{id}/consume [post]
+func ConsumeBatchHandler(c *gin.Context) {
+	req := ConsumeRequest{Portions: 1}
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if req.Portions <= 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "portions must be a positive integer"})
+		return
+	}
+
+	cookBatches.mu.Lock()
+	defer cookBatches.mu.Unlock()
+	b, ok := cookBatches.batches[c.Param("id")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+		return
+	}
+	if req.Portions > b.Remaining {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "not enough portions left", "remaining": b.Remaining})
+		return
+	}
+	b.Remaining -= req.Portions
+	c.JSON(http.StatusOK, *b)
+}
+
+// ListBatchesHandler lists the batches that still have portions left.
+//
+// @Summary List active batches
+// @Tags batches
+// @Produce json
+// @Success 200 {array} CookBatch
+// @Router /batches [get]
+func ListBatchesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, cookBatches.active())
+}
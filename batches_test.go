@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func activeBatches(t *testing.T, router http.Handler) []CookBatch {
+	t.Helper()
+	w := serve(router, http.MethodGet, "/batches", "")
+	expectStatus(t, w, http.StatusOK)
+	return decodeBody[[]CookBatch](t, w)
+}
+
+func TestCookBatchConsumedToZero(t *testing.T) {
+	r := testRecipe("chili", "Chili")
+	r.Servings = 4
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodPost, "/recipe/chili/cook-batch", `{"servings":6}`)
+	expectStatus(t, w, http.StatusCreated)
+	batch := decodeBody[CookBatch](t, w)
+	if batch.RecipeID != "chili" || batch.ServingsMade != 6 || batch.Remaining != 6 {
+		t.Fatalf("batch = %+v, want 6 servings of chili", batch)
+	}
+	if got := activeBatches(t, router); len(got) != 1 || got[0].ID != batch.ID {
+		t.Fatalf("active batches = %+v, want the new batch", got)
+	}
+
+	consume := "/batches/" + batch.ID + "/consume"
+	for _, step := range []struct {
+		body      string
+		status    int
+		remaining int
+	}{
+		{"", http.StatusOK, 5},
+		{`{"portions":3}`, http.StatusOK, 2},
+		{`{"portions":3}`, http.StatusUnprocessableEntity, 2},
+		{`{"portions":0}`, http.StatusUnprocessableEntity, 2},
+		{`{"portions":2}`, http.StatusOK, 0},
+	} {
+		w := serve(router, http.MethodPost, consume, step.body)
+		expectStatus(t, w, step.status)
+		if step.status == http.StatusOK {
+			if got := decodeBody[CookBatch](t, w).Remaining; got != step.remaining {
+				t.Errorf("after consuming %s: remaining %d, want %d", step.body, got, step.remaining)
+			}
+		}
+	}
+	if got := activeBatches(t, router); len(got) != 0 {
+		t.Errorf("active batches = %+v, want none once eaten", got)
+	}
+	expectStatus(t, serve(router, http.MethodPost, consume, ""), http.StatusUnprocessableEntity)
+}
+
+func TestCookBatchDefaultsAndErrors(t *testing.T) {
+	stew := testRecipe("stew", "Stew")
+	stew.Servings = 4
+	router := newTestRouter(t, nil, stew, testRecipe("toast", "Toast"))
+
+	w := serve(router, http.MethodPost, "/recipe/stew/cook-batch", "")
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[CookBatch](t, w).Remaining; got != 4 {
+		t.Errorf("remaining = %d, want the recipe's 4 servings", got)
+	}
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/toast/cook-batch", ""), http.StatusUnprocessableEntity)
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/missing/cook-batch", `{"servings":2}`), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodPost, "/batches/missing/consume", ""), http.StatusNotFound)
+}
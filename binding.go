@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// errEmptyBody is returned by bindRecipe when the request has no body.
+var errEmptyBody = errors.New("request body is required")
+
+// hasBody reports whether req carries at least one byte of body. When the
+// length is unknown it peeks at the stream and puts the byte back.
+func hasBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+		return false
+	}
+	if req.ContentLength > 0 {
+		return true
+	}
+	br := bufio.NewReader(req.Body)
+	if _, err := br.Peek(1); err != nil {
+		return false
+	}
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, req.Body}
+	return true
+}
+
+// bindRecipe decodes the request body into r. JSON is the primary format;
+// application/x-www-form-urlencoded bodies are accepted for clients that
+// cannot send JSON. An empty body fails with errEmptyBody rather than a
+// decoder EOF.
+func bindRecipe(c *gin.Context, r *Recipe) error {
+	if !hasBody(c.Request) {
+		return errEmptyBody
+	}
+	if c.ContentType() == binding.MIMEPOSTForm {
+		if err := c.Request.ParseForm(); err != nil {
+			return err
+		}
+		return recipeFromForm(c.Request.PostForm, r)
+	}
+	return bindJSON(c, r)
+}
+
+// strictJSON reports whether to decode the request's JSON strictly: always
+// under config.StrictJSON, otherwise when the client sends
+// "X-Strict-JSON: true".
+func strictJSON(c *gin.Context) bool {
+	return config.StrictJSON || c.GetHeader("X-Strict-JSON") == "true"
+}
+
+// bindJSON decodes the JSON body, of at most maxJSONBytes, into v and runs
+// the binding validators. In strict mode fields that v does not declare are
+// rejected by name instead of being silently dropped.
+func bindJSON(c *gin.Context, v any) error {
+	limitBody(c)
+	if !strictJSON(c) {
+		return numberError(c.ShouldBindJSON(v))
+	}
+	if c.Request.Body == nil {
+		return errEmptyBody
+	}
+	dec := json.NewDecoder(c.Request.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("unknown field %s", field)
+		}
+		return numberError(err)
+	}
+	return binding.Validator.ValidateStruct(v)
+}
+
+// numberError rewords a JSON number that does not fit an integer field,
+// such as a fraction or a value beyond the int64 range, into a message
+// naming the field and its range. Integer fields are decoded exactly, so
+// such values are rejected rather than rounded. Other errors pass through.
+func numberError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || !strings.HasPrefix(typeErr.Value, "number") || typeErr.Type.Kind() != reflect.Int {
+		return err
+	}
+	got := strings.TrimSpace(strings.TrimPrefix(typeErr.Value, "number"))
+	return fmt.Errorf("%s must be a whole number from %d to %d, got %s", typeErr.Field, math.MinInt, math.MaxInt, got)
+}
+
+// maxJSONBytes caps JSON request bodies, which are read whole before they
+// are decoded. It leaves room to replace a large collection in one request.
+const maxJSONBytes = 10 << 20
+
+// limitBody caps the request body at maxJSONBytes. Reading past it fails
+// with an error for which tooLarge reports true.
+func limitBody(c *gin.Context) {
+	if c.Request.Body != nil {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxJSONBytes)
+	}
+}
+
+// tooLarge reports whether err comes from reading a body past its limit.
+func tooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// bindStatus maps a bind error to its response status: a body that decoded
+// but failed a binding rule is 422, one over maxJSONBytes is 413, and
+// anything that could not be parsed is 400.
+func bindStatus(err error) int {
+	var verrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		return http.StatusUnprocessableEntity
+	case tooLarge(err):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// formList reads a slice field either from repeated keys or, for a single
+// value, by splitting it on commas.
+func formList(form url.Values, key string) []string {
+	values, ok := form[key]
+	if !ok {
+		return nil
+	}
+	if len(values) == 1 {
+		return splitList(values[0])
+	}
+	return values
+}
+
+func formInt(form url.Values, key string) (int, error) {
+	v := strings.TrimSpace(form.Get(key))
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+	return n, nil
+}
+
+// recipeFromForm fills r from form-encoded fields named like their JSON
+// counterparts.
+func recipeFromForm(form url.Values, r *Recipe) error {
+	r.Name = form.Get("name")
+	r.Category = form.Get("category")
+	r.Difficulty = form.Get("difficulty")
+	r.YieldText = form.Get("yieldText")
+	r.Currency = form.Get("currency")
+	r.Tags = formList(form, "tags")
+	r.Ingredients = formList(form, "ingredients")
+	r.Instructions = formList(form, "instructions")
+	r.Allergens = formList(form, "allergens")
+	r.Equipment = formList(form, "equipment")
+
+	var err error
+	if r.Servings, err = formInt(form, "servings"); err != nil {
+		return err
+	}
+	if r.PrepTime, err = formInt(form, "prepTime"); err != nil {
+		return err
+	}
+	if r.CookTime, err = formInt(form, "cookTime"); err != nil {
+		return err
+	}
+	if r.CostCents, err = formInt(form, "costCents"); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const formContentType = "application/x-www-form-urlencoded"
+
+func TestFormRecipeMatchesJSON(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes", `{"name":"Pancakes","category":"breakfast","tags":["sweet","quick"],`+
+		`"ingredients":["1 cup flour","2 eggs"],"instructions":["Mix.","Fry."],"servings":4,"prepTime":10}`)
+	expectStatus(t, w, http.StatusCreated)
+	fromJSON := decodeBody[Recipe](t, w)
+
+	form := url.Values{
+		"name":         {"Pancakes"},
+		"category":     {"breakfast"},
+		"tags":         {"sweet, quick"},
+		"ingredients":  {"1 cup flour", "2 eggs"},
+		"instructions": {"Mix.,Fry."},
+		"servings":     {"4"},
+		"prepTime":     {"10"},
+	}
+	w = serve(router, http.MethodPost, "/recipes", form.Encode(), "Content-Type", formContentType)
+	expectStatus(t, w, http.StatusCreated)
+	fromForm := decodeBody[Recipe](t, w)
+
+	for _, r := range []*Recipe{&fromJSON, &fromForm} {
+		r.ID, r.PublishedAt, r.UpdatedAt = "", Timestamp{}, Timestamp{}
+	}
+	if !reflect.DeepEqual(fromJSON, fromForm) {
+		t.Errorf("form recipe = %+v, want %+v", fromForm, fromJSON)
+	}
+}
+
+func TestFormUpdate(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	form := url.Values{"name": {"Tomato soup"}, "ingredients": {"4 tomatoes"}, "instructions": {"Simmer."}, "cookTime": {"30"}}
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/r1", form.Encode(), "Content-Type", formContentType), http.StatusOK)
+	if got := storedRecipe(t, "r1"); got.Name != "Tomato soup" || got.CookTime != 30 {
+		t.Errorf("stored %+v, want the form's name and cook time", got)
+	}
+
+	form.Set("cookTime", "half an hour")
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/r1", form.Encode(), "Content-Type", formContentType), http.StatusBadRequest)
+}
+
+func TestEmptyBodyIsRejected(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	for _, tc := range []struct {
+		method, target string
+		body           io.Reader
+		length         int64
+	}{
+		{http.MethodPost, "/recipes", nil, 0},
+		{http.MethodPost, "/recipes", strings.NewReader(""), 0},
+		{http.MethodPut, "/recipe/r1", nil, 0},
+		{http.MethodPost, "/recipes", strings.NewReader(""), -1},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, tc.body)
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = tc.length
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		expectStatus(t, w, http.StatusBadRequest)
+		if got := decodeBody[ErrorResponse](t, w).Error; got != "request body is required" {
+			t.Errorf("%s %s with length %d: error %q", tc.method, tc.target, tc.length, got)
+		}
+	}
+}
+
+func TestStrictJSON(t *testing.T) {
+	body := `{"name":"Toast","tag":["quick"],"ingredients":["bread"],"instructions":["Toast."]}`
+
+	router := newTestRouter(t, nil)
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", body), http.StatusCreated)
+
+	w := serve(router, http.MethodPost, "/recipes", body, "X-Strict-JSON", "true")
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decodeBody[ErrorResponse](t, w).Error; got != `unknown field "tag"` {
+		t.Errorf("error = %q, want it to name the field", got)
+	}
+
+	router = newTestRouter(t, func(cfg *Config) { cfg.StrictJSON = true })
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", body), http.StatusBadRequest)
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", newRecipeBody), http.StatusCreated)
+}
+
+func TestParseErrorsAre400AndSemanticErrors422(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	for _, body := range []string{
+		`{"name":"Toast",`,
+		`["not","an","object"]`,
+		`{"name":"Toast","servings":"four"}`,
+		`{"name":"Toast","prepTime":1.5}`,
+	} {
+		expectStatus(t, serve(router, http.MethodPost, "/recipes", body), http.StatusBadRequest)
+		expectStatus(t, serve(router, http.MethodPut, "/recipe/r1", body), http.StatusBadRequest)
+	}
+	for _, body := range []string{
+		`{"name":"","ingredients":["bread"],"instructions":["Toast."]}`,
+		`{"name":"Toast","category":"brunch","ingredients":["bread"],"instructions":["Toast."]}`,
+		`{"name":"Toast","difficulty":"trivial","ingredients":["bread"],"instructions":["Toast."]}`,
+		`{"name":"Toast","prepTime":-5,"ingredients":["bread"],"instructions":["Toast."]}`,
+	} {
+		expectStatus(t, serve(router, http.MethodPost, "/recipes", body), http.StatusUnprocessableEntity)
+		expectStatus(t, serve(router, http.MethodPut, "/recipe/r1", body), http.StatusUnprocessableEntity)
+	}
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/batch-get", `{"ids":[]`), http.StatusBadRequest)
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/batch-get", `{}`), http.StatusUnprocessableEntity)
+}
+
+func TestLargeIntegersRoundTrip(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	const big = 9007199254740993 // 2^53 + 1, which a float64 rounds down
+
+	w := serve(router, http.MethodPost, "/recipes", `{"name":"Caviar","ingredients":["1 tin caviar"],"instructions":["Serve."],"costCents":9007199254740993,"currency":"USD"}`)
+	expectStatus(t, w, http.StatusCreated)
+	created := decodeBody[Recipe](t, w)
+	if created.CostCents != big || storedRecipe(t, created.ID).CostCents != big {
+		t.Errorf("created costCents = %d, want %d", created.CostCents, big)
+	}
+	w = serve(router, http.MethodGet, "/recipe/"+created.ID, "")
+	expectStatus(t, w, http.StatusOK)
+	if !strings.Contains(w.Body.String(), `"costCents":9007199254740993`) {
+		t.Errorf("body %s does not carry the exact costCents", w.Body.String())
+	}
+
+	w = mergePatchRequest(router, "r1", `{"costCents":9007199254740993,"currency":"USD"}`)
+	expectStatus(t, w, http.StatusOK)
+	if got := storedRecipe(t, "r1").CostCents; got != big {
+		t.Errorf("merge-patched costCents = %d, want %d", got, big)
+	}
+}
+
+func TestNonIntegerNumbersAreRejected(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	for _, v := range []string{"1.5", "1e3", "9223372036854775808"} {
+		want := "costCents must be a whole number from -9223372036854775808 to 9223372036854775807, got " + v
+		for name, w := range map[string]*httptest.ResponseRecorder{
+			"POST":  serve(router, http.MethodPost, "/recipes", `{"name":"Caviar","ingredients":["1 tin caviar"],"instructions":["Serve."],"currency":"USD","costCents":`+v+`}`),
+			"PATCH": mergePatchRequest(router, "r1", `{"costCents":`+v+`}`),
+		} {
+			expectStatus(t, w, http.StatusBadRequest)
+			if got := decodeBody[ErrorResponse](t, w).Error; got != want {
+				t.Errorf("%s costCents %s: error = %q, want %q", name, v, got, want)
+			}
+		}
+	}
+}
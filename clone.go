@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cloneRecipe returns an unsaved copy of r named as a copy, sharing no
+// slices or maps with it and not linked to any parent. With servings > 0 the
+// ingredient quantities are scaled from r's servings to servings; r must
+// then have servings of its own.
+func cloneRecipe(r Recipe, servings int) (Recipe, error) {
+	clone := r
+	clone.Name = r.Name + " (copy)"
+	clone.Tags = slices.Clone(r.Tags)
+	clone.Ingredients = slices.Clone(r.Ingredients)
+	clone.Instructions = slices.Clone(r.Instructions)
+	clone.Steps = slices.Clone(r.Steps)
+	clone.Allergens = slices.Clone(r.Allergens)
+	clone.Equipment = slices.Clone(r.Equipment)
+	clone.Videos = slices.Clone(r.Videos)
+	clone.Translations = cloneTranslations(r.Translations)
+	clone.Thumbnail = ""
+	clone.Pinned = false
+	clone.ParentID = ""
+	if servings == 0 {
+		return clone, nil
+	}
+	if r.Servings <= 0 {
+		return clone, fmt.Errorf("recipe has no numeric servings to scale from")
+	}
+	factor := float64(servings) / float64(r.Servings)
+	for i, line := range clone.Ingredients {
+		clone.Ingredients[i] = scaleIngredient(line, factor)
+	}
+	clone.Servings = servings
+	return clone, nil
+}
+
+// CloneRecipeHandler saves a copy of a recipe under a new ID. With
+// ?servings=N the copy's ingredients are scaled to N servings in the same
+// step, which is handy for half or double batch variants.
+//
+// @Summary Clone a recipe
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param servings query int false "Scale the copy to this many servings"
+// @Success 201 {object} Recipe
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse
+// @Router /recipe/{id}/clone [post]
+func CloneRecipeHandler(c *gin.Context) {
+	servings := 0
+	if v := c.Query("servings"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "servings must be a positive integer"})
+			return
+		}
+		servings = n
+	}
+
+	recipesMu.Lock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	clone, err := cloneRecipe(recipes[i], servings)
+	if err != nil {
+		recipesMu.Unlock()
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	evicted, ok := config.Capacity.makeRoom()
+	if !ok {
+		recipesMu.Unlock()
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)})
+		return
+	}
+	clone.ID = newRecipeID()
+	clone.PublishedAt = Timestamp{time.Now()}
+	clone.UpdatedAt = clone.PublishedAt
+	insertRecipe(clone)
+	recipesMu.Unlock()
+
+	if evicted != "" {
+		auditor.record(c, "evict", evicted)
+	}
+	auditor.record(c, "create", clone.ID)
+	respondRecipe(c, http.StatusCreated, clone)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestCloneKeepsOriginal(t *testing.T) {
+	r := testRecipe("r1", "Pancakes", "breakfast")
+	r.Pinned = true
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodPost, "/recipe/r1/clone", "")
+	expectStatus(t, w, http.StatusCreated)
+	clone := decodeBody[Recipe](t, w)
+	if clone.ID == "r1" || clone.Name != "Pancakes (copy)" || clone.Pinned {
+		t.Errorf("clone = %+v, want a new unpinned copy", clone)
+	}
+	if !slices.Equal(clone.Ingredients, r.Ingredients) || !slices.Equal(clone.Tags, r.Tags) {
+		t.Errorf("clone ingredients %q tags %q, want the original's", clone.Ingredients, clone.Tags)
+	}
+	storedRecipe(t, clone.ID)
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/missing/clone", ""), http.StatusNotFound)
+}
+
+func TestCloneScalesServings(t *testing.T) {
+	r := testRecipe("r1", "Pancakes")
+	r.Servings = 4
+	r.Ingredients = []string{"4 eggs", "1 cup milk", "salt to taste"}
+	router := newTestRouter(t, nil, r, testRecipe("noservings", "Soup"))
+
+	w := serve(router, http.MethodPost, "/recipe/r1/clone?servings=2", "")
+	expectStatus(t, w, http.StatusCreated)
+	clone := decodeBody[Recipe](t, w)
+	if clone.Servings != 2 {
+		t.Errorf("servings = %d, want 2", clone.Servings)
+	}
+	if want := []string{"2 eggs", "1/2 cup milk", "salt to taste"}; !slices.Equal(clone.Ingredients, want) {
+		t.Errorf("ingredients = %q, want %q", clone.Ingredients, want)
+	}
+	if got := storedRecipe(t, "r1"); got.Servings != 4 || got.Ingredients[0] != "4 eggs" {
+		t.Errorf("original changed to %+v", got)
+	}
+
+	for _, q := range []string{"0", "-2", "half"} {
+		expectStatus(t, serve(router, http.MethodPost, "/recipe/r1/clone?servings="+q, ""), http.StatusBadRequest)
+	}
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/noservings/clone?servings=2", ""), http.StatusUnprocessableEntity)
+}
+
+func TestCloneCopiesTranslations(t *testing.T) {
+	r := testRecipe("r1", "Pancakes")
+	r.Translations = map[string]Translation{
+		"fr": {Name: "Crêpes", Ingredients: []string{"farine"}, Instructions: []string{"Mélanger."}},
+	}
+	newTestRouter(t, nil, r)
+
+	clone, err := cloneRecipe(recipes[findRecipe("r1")], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone.Translations["fr"].Ingredients[0] = "sucre"
+	clone.Translations["fr"].Instructions[0] = "Cuire."
+	clone.Translations["de"] = Translation{Name: "Pfannkuchen"}
+
+	got := storedRecipe(t, "r1").Translations
+	if fr := got["fr"]; len(got) != 1 || fr.Ingredients[0] != "farine" || fr.Instructions[0] != "Mélanger." {
+		t.Errorf("stored translations changed to %+v", got)
+	}
+}
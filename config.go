@@ -0,0 +1,361 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"terrenceng/recipes-api/docs"
+)
+
+// Config is every setting the server reads from the environment. It is
+// loaded and validated once at startup by loadConfig; code reads the
+// package-level config rather than the environment.
+type Config struct {
+	Port        int
+	RecipesFile string
+	StrictLoad  bool
+	Capacity    capacityPolicy
+	AuditLog    string
+
+	// APIKeys maps each API key to the user it authenticates.
+	APIKeys           map[string]string
+	DefaultTags       []string
+	IDScheme          idScheme
+	StrictJSON        bool
+	Features          featureSet
+	ConfirmDeletes    bool
+	WriteContentTypes []string
+	RecentHistory     int
+	SearchMaxResults  int
+	DefaultSort       sortSpec
+	SortLocale        language.Tag
+
+	RateLimit       RateLimitConfig
+	ForceHTTPS      bool
+	HTMLErrors      bool
+	PublicBaseURL   string
+	CORS            CORSConfig
+	SecurityHeaders SecurityHeaders
+	Server          ServerConfig
+	MetricsBuckets  []float64
+	ContentFilter   ContentFilterConfig
+	AutoTag         AutoTagConfig
+	NutritionTable  string
+	Swagger         SwaggerConfig
+}
+
+// ContentFilterConfig enables the banned-word filter; see loadContentFilter.
+type ContentFilterConfig struct {
+	Enabled   bool
+	Mask      bool
+	WordsFile string
+}
+
+// AutoTagConfig enables tags derived from ingredients; see loadAutoTags.
+type AutoTagConfig struct {
+	Enabled   bool
+	RulesFile string
+}
+
+// SwaggerConfig overrides where the generated spec says the API is served.
+type SwaggerConfig struct {
+	Host     string
+	BasePath string
+	Schemes  []string
+}
+
+// defaultConfig is the configuration with no environment variables set.
+func defaultConfig() Config {
+	return Config{
+		Port:        7778,
+		RecipesFile: "recipes.json",
+		APIKeys:     map[string]string{},
+		DefaultTags: []string{},
+		IDScheme:    xidScheme,
+		WriteContentTypes: []string{
+			"application/json",
+			"application/x-www-form-urlencoded",
+		},
+		RecentHistory:    20,
+		SearchMaxResults: defaultSearchMaxResults,
+		DefaultSort:      sortSpec{field: "publishedAt", desc: true},
+		SortLocale:       language.English,
+		CORS: CORSConfig{
+			AllowOrigins:  []string{"*"},
+			AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+			AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-KEY", "X-Strict-JSON"},
+			ExposeHeaders: []string{"X-Request-ID", "ETag", "Link"},
+			MaxAge:        600,
+		},
+		SecurityHeaders: SecurityHeaders{
+			"X-Content-Type-Options": "nosniff",
+			"X-Frame-Options":        "DENY",
+			"Referrer-Policy":        "no-referrer",
+		},
+		HTMLErrors:     true,
+		Server:         defaultServerConfig,
+		MetricsBuckets: defaultBuckets,
+	}
+}
+
+// config is the active configuration. It holds the defaults until main
+// replaces it with the validated environment.
+var config = defaultConfig()
+
+// envReader parses environment values into a Config, collecting a message
+// for every invalid value instead of stopping at the first.
+type envReader struct {
+	getenv func(string) string
+	errs   []error
+}
+
+func (e *envReader) fail(key, format string, args ...any) {
+	e.errs = append(e.errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+}
+
+func (e *envReader) str(key string, dst *string) {
+	if v := e.getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func (e *envReader) list(key string, dst *[]string) {
+	if v := e.getenv(key); v != "" {
+		*dst = splitList(v)
+	}
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	v := e.getenv(key)
+	if v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.fail(key, "must be true or false, got %q", v)
+		return
+	}
+	*dst = b
+}
+
+// int reads an integer of at least min.
+func (e *envReader) int(key string, min int, dst *int) {
+	v := e.getenv(key)
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < min {
+		e.fail(key, "must be an integer of at least %d, got %q", min, v)
+		return
+	}
+	*dst = n
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	v := e.getenv(key)
+	if v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		e.fail(key, "must be a non-negative duration such as 30s, got %q", v)
+		return
+	}
+	*dst = d
+}
+
+// float reads a non-negative number.
+func (e *envReader) float(key string, dst *float64) {
+	v := e.getenv(key)
+	if v == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || f < 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		e.fail(key, "must be a non-negative number, got %q", v)
+		return
+	}
+	*dst = f
+}
+
+// oneOf reads a value that must be one of allowed.
+func (e *envReader) oneOf(key string, dst *string, allowed ...string) {
+	v := e.getenv(key)
+	if v == "" {
+		return
+	}
+	for _, a := range allowed {
+		if v == a {
+			*dst = v
+			return
+		}
+	}
+	e.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), v)
+}
+
+// loadConfig builds a Config from the environment variables returned by
+// getenv, starting from defaultConfig. The error lists every invalid or
+// missing value, one per line.
+func loadConfig(getenv func(string) string) (Config, error) {
+	cfg := defaultConfig()
+	e := &envReader{getenv: getenv}
+
+	e.int("PORT", 1, &cfg.Port)
+	if cfg.Port > 65535 {
+		e.fail("PORT", "must be at most 65535, got %d", cfg.Port)
+	}
+	e.str("RECIPES_FILE", &cfg.RecipesFile)
+	e.bool("STRICT_LOAD", &cfg.StrictLoad)
+	e.int("MAX_RECIPES", 0, &cfg.Capacity.max)
+	policy := "reject"
+	e.oneOf("MAX_RECIPES_POLICY", &policy, "reject", "evict-oldest")
+	cfg.Capacity.evictOldest = policy == "evict-oldest"
+	e.str("AUDIT_LOG", &cfg.AuditLog)
+
+	for _, pair := range splitList(getenv("API_KEYS")) {
+		key, user, ok := strings.Cut(pair, ":")
+		if !ok || key == "" || user == "" {
+			e.fail("API_KEYS", "entries must be key:user, got %q", pair)
+			continue
+		}
+		cfg.APIKeys[key] = user
+	}
+	cfg.DefaultTags = normalizeTags(splitList(getenv("DEFAULT_TAGS")))
+	scheme := cfg.IDScheme.name
+	e.oneOf("ID_SCHEME", &scheme, "xid", "uuidv7")
+	if scheme == "uuidv7" {
+		cfg.IDScheme = uuidv7Scheme
+	}
+	e.bool("STRICT_JSON", &cfg.StrictJSON)
+	if v := getenv("FEATURES"); v != "" {
+		cfg.Features = featureSet{}
+		for _, f := range normalizeList(splitList(v)) {
+			if f == "none" {
+				continue
+			}
+			if !knownFeatures[f] {
+				e.fail("FEATURES", "unknown feature %q (known: %s)", f, strings.Join(sortedKeys(knownFeatures), ", "))
+				continue
+			}
+			cfg.Features[f] = true
+		}
+	}
+	e.bool("CONFIRM_DELETES", &cfg.ConfirmDeletes)
+	e.list("ALLOWED_CONTENT_TYPES", &cfg.WriteContentTypes)
+	e.int("RECENT_HISTORY_SIZE", 1, &cfg.RecentHistory)
+	e.int("SEARCH_MAX_RESULTS", 1, &cfg.SearchMaxResults)
+	e.oneOf("DEFAULT_SORT", &cfg.DefaultSort.field, sortedKeys(sortableFields)...)
+	order := "desc"
+	e.oneOf("DEFAULT_ORDER", &order, "asc", "desc")
+	cfg.DefaultSort.desc = order == "desc"
+	if v := getenv("SORT_LOCALE"); v != "" {
+		tag, err := language.Parse(v)
+		if err != nil {
+			e.fail("SORT_LOCALE", "must be a BCP 47 language tag, got %q", v)
+		} else {
+			cfg.SortLocale = tag
+		}
+	}
+
+	e.float("RATE_LIMIT_RPS", &cfg.RateLimit.RPS)
+	cfg.RateLimit.AuthRPS = cfg.RateLimit.RPS
+	e.float("RATE_LIMIT_AUTH_RPS", &cfg.RateLimit.AuthRPS)
+	e.bool("FORCE_HTTPS", &cfg.ForceHTTPS)
+	e.bool("HTML_ERRORS", &cfg.HTMLErrors)
+	if v := getenv("PUBLIC_BASE_URL"); v != "" {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+			e.fail("PUBLIC_BASE_URL", "must be an http or https URL such as https://recipes.example.com, got %q", v)
+		} else {
+			cfg.PublicBaseURL = strings.TrimSuffix(v, "/")
+		}
+	}
+	e.list("CORS_ALLOW_ORIGINS", &cfg.CORS.AllowOrigins)
+	e.list("CORS_EXPOSE_HEADERS", &cfg.CORS.ExposeHeaders)
+	e.int("CORS_MAX_AGE", 0, &cfg.CORS.MaxAge)
+	if v := getenv("SECURITY_HEADERS"); v == "false" {
+		cfg.SecurityHeaders = SecurityHeaders{}
+	} else {
+		for env, name := range securityHeaderVars {
+			switch v := getenv(env); v {
+			case "":
+			case "off":
+				delete(cfg.SecurityHeaders, name)
+			default:
+				cfg.SecurityHeaders[name] = v
+			}
+		}
+	}
+	e.duration("READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	e.duration("WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	e.duration("IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+	e.int("MAX_HEADER_BYTES", 1, &cfg.Server.MaxHeaderBytes)
+	e.int("MAX_QUERY_BYTES", 0, &cfg.Server.MaxQueryBytes)
+	e.bool("ENABLE_H2C", &cfg.Server.H2C)
+	if v := getenv("METRICS_BUCKETS"); v != "" {
+		buckets, err := parseBuckets(v)
+		if err != nil {
+			e.fail("METRICS_BUCKETS", "%v", err)
+		} else {
+			cfg.MetricsBuckets = buckets
+		}
+	}
+
+	e.bool("CONTENT_FILTER", &cfg.ContentFilter.Enabled)
+	mode := "reject"
+	e.oneOf("CONTENT_FILTER_MODE", &mode, "reject", "mask")
+	cfg.ContentFilter.Mask = mode == "mask"
+	e.str("CONTENT_FILTER_WORDS", &cfg.ContentFilter.WordsFile)
+	if cfg.ContentFilter.Enabled && cfg.ContentFilter.WordsFile == "" {
+		e.fail("CONTENT_FILTER_WORDS", "is required when CONTENT_FILTER is true")
+	}
+
+	e.bool("AUTO_TAG", &cfg.AutoTag.Enabled)
+	e.str("AUTO_TAG_RULES", &cfg.AutoTag.RulesFile)
+	if cfg.AutoTag.Enabled && cfg.AutoTag.RulesFile == "" {
+		e.fail("AUTO_TAG_RULES", "is required when AUTO_TAG is true")
+	}
+
+	e.str("NUTRITION_TABLE", &cfg.NutritionTable)
+
+	e.str("SWAGGER_HOST", &cfg.Swagger.Host)
+	e.str("SWAGGER_BASE_PATH", &cfg.Swagger.BasePath)
+	e.list("SWAGGER_SCHEMES", &cfg.Swagger.Schemes)
+
+	return cfg, errors.Join(e.errs...)
+}
+
+// applyConfig makes cfg the active configuration. It must run before the
+// router is built or any request is served.
+func applyConfig(cfg Config) {
+	config = cfg
+	requestDuration.buckets = cfg.MetricsBuckets
+	if cfg.Swagger.Host != "" {
+		docs.SwaggerInfo.Host = cfg.Swagger.Host
+	}
+	if cfg.Swagger.BasePath != "" {
+		docs.SwaggerInfo.BasePath = cfg.Swagger.BasePath
+	}
+	if len(cfg.Swagger.Schemes) > 0 {
+		docs.SwaggerInfo.Schemes = cfg.Swagger.Schemes
+	}
+}
+
+// configFromEnv loads and applies the process environment.
+func configFromEnv() error {
+	cfg, err := loadConfig(os.Getenv)
+	if err != nil {
+		return err
+	}
+	applyConfig(cfg)
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"terrenceng/recipes-api/docs"
+)
+
+func TestSwaggerInfoFromEnv(t *testing.T) {
+	saved := *docs.SwaggerInfo
+	t.Cleanup(func() {
+		*docs.SwaggerInfo = saved
+		applyConfig(defaultConfig())
+	})
+	t.Setenv("SWAGGER_HOST", "api.example.com")
+	t.Setenv("SWAGGER_BASE_PATH", "/v1")
+	t.Setenv("SWAGGER_SCHEMES", "https, http")
+	if err := configFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := serve(setupRouter(), http.MethodGet, "/swagger/doc.json", "")
+	expectStatus(t, w, http.StatusOK)
+	spec := decodeBody[struct {
+		Host     string   `json:"host"`
+		BasePath string   `json:"basePath"`
+		Schemes  []string `json:"schemes"`
+	}](t, w)
+	if spec.Host != "api.example.com" || spec.BasePath != "/v1" || !slices.Equal(spec.Schemes, []string{"https", "http"}) {
+		t.Errorf("spec host %q, basePath %q, schemes %q; want api.example.com, /v1, [https http]", spec.Host, spec.BasePath, spec.Schemes)
+	}
+}
+
+// envMap returns a getenv that reads from env.
+func envMap(env map[string]string) func(string) string {
+	return func(k string) string { return env[k] }
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := loadConfig(envMap(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 7778 || cfg.RecipesFile != "recipes.json" || cfg.IDScheme.name != "xid" ||
+		cfg.SearchMaxResults != defaultSearchMaxResults || !cfg.Features.on("admin") {
+		t.Errorf("defaults = %+v", cfg)
+	}
+}
+
+func TestLoadConfigValid(t *testing.T) {
+	cfg, err := loadConfig(envMap(map[string]string{
+		"PORT":               "9090",
+		"RECIPES_FILE":       "/data/recipes.json",
+		"MAX_RECIPES":        "100",
+		"MAX_RECIPES_POLICY": "evict-oldest",
+		"API_KEYS":           "k1:alice, k2:bob",
+		"DEFAULT_TAGS":       "Team, quick",
+		"ID_SCHEME":          "uuidv7",
+		"FEATURES":           "search, admin",
+		"STRICT_JSON":        "true",
+		"DEFAULT_SORT":       "name",
+		"DEFAULT_ORDER":      "asc",
+		"RATE_LIMIT_RPS":     "2.5",
+		"PUBLIC_BASE_URL":    "https://recipes.example.com/",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 9090 || cfg.RecipesFile != "/data/recipes.json" {
+		t.Errorf("port %d, file %q", cfg.Port, cfg.RecipesFile)
+	}
+	if cfg.Capacity != (capacityPolicy{max: 100, evictOldest: true}) {
+		t.Errorf("capacity = %+v", cfg.Capacity)
+	}
+	if cfg.APIKeys["k1"] != "alice" || cfg.APIKeys["k2"] != "bob" {
+		t.Errorf("API keys = %v", cfg.APIKeys)
+	}
+	if !slices.Equal(cfg.DefaultTags, []string{"team", "quick"}) {
+		t.Errorf("default tags = %q", cfg.DefaultTags)
+	}
+	if cfg.IDScheme.name != "uuidv7" || !cfg.StrictJSON {
+		t.Errorf("id scheme %s, strict JSON %v", cfg.IDScheme.name, cfg.StrictJSON)
+	}
+	if !cfg.Features.on("search") || !cfg.Features.on("admin") || cfg.Features.on("batch") {
+		t.Errorf("features = %v", cfg.Features)
+	}
+	if cfg.DefaultSort != (sortSpec{field: "name"}) {
+		t.Errorf("default sort = %+v", cfg.DefaultSort)
+	}
+	if cfg.RateLimit.RPS != 2.5 || cfg.RateLimit.AuthRPS != 2.5 {
+		t.Errorf("rate limit = %+v", cfg.RateLimit)
+	}
+	if cfg.PublicBaseURL != "https://recipes.example.com" {
+		t.Errorf("public base URL = %q", cfg.PublicBaseURL)
+	}
+}
+
+func TestLoadConfigReportsEveryError(t *testing.T) {
+	_, err := loadConfig(envMap(map[string]string{
+		"PORT":        "http",
+		"MAX_RECIPES": "-3",
+		"API_KEYS":    "nouser",
+		"ID_SCHEME":   "uuid4",
+		"STRICT_JSON": "maybe",
+		"FEATURES":    "search, teleport",
+		"AUTO_TAG":    "true",
+	}))
+	if err == nil {
+		t.Fatal("invalid config was accepted")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	for _, key := range []string{"PORT", "MAX_RECIPES", "API_KEYS", "ID_SCHEME", "STRICT_JSON", "FEATURES", "AUTO_TAG_RULES"} {
+		found := false
+		for _, l := range lines {
+			found = found || strings.HasPrefix(l, key+": ")
+		}
+		if !found {
+			t.Errorf("error does not report %s:\n%v", key, err)
+		}
+	}
+	if len(lines) != 7 {
+		t.Errorf("got %d errors, want 7:\n%v", len(lines), err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// deleteTokenTTL is how long a delete confirmation token stays valid.
+const deleteTokenTTL = time.Minute
+
+// deleteToken is an issued confirmation for deleting one recipe.
+type deleteToken struct {
+	id      string
+	expires time.Time
+}
+
+// deleteConfirmations holds the outstanding tokens of the two-step delete
+// enabled by config.ConfirmDeletes.
+type deleteConfirmations struct {
+	mu     sync.Mutex
+	tokens map[string]deleteToken
+}
+
+var pendingDeletes = &deleteConfirmations{tokens: make(map[string]deleteToken)}
+
+// issue returns a new token confirming the deletion of id, valid until the
+// returned time, and drops tokens that have expired.
+func (d *deleteConfirmations) issue(id string, now time.Time) (string, time.Time) {
+	var buf [16]byte
+	rand.Read(buf[:])
+	token := hex.EncodeToString(buf[:])
+	expires := now.Add(deleteTokenTTL)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for t, pending := range d.tokens {
+		if !now.Before(pending.expires) {
+			delete(d.tokens, t)
+		}
+	}
+	d.tokens[token] = deleteToken{id: id, expires: expires}
+	return token, expires
+}
+
+// redeem reports whether token confirms deleting id at now. A token is
+// used up by redeeming it, whatever the outcome.
+func (d *deleteConfirmations) redeem(token, id string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending, ok := d.tokens[token]
+	delete(d.tokens, token)
+	return ok && pending.id == id && now.Before(pending.expires)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// confirmToken requests a delete confirmation token for id.
+func confirmToken(t *testing.T, router http.Handler, id string) string {
+	t.Helper()
+	w := serve(router, http.MethodDelete, "/recipe/"+id, "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[struct {
+		ConfirmToken string    `json:"confirmToken"`
+		ExpiresAt    time.Time `json:"expiresAt"`
+	}](t, w)
+	if got.ConfirmToken == "" {
+		t.Fatalf("no confirmToken in %s", w.Body.String())
+	}
+	if until := time.Until(got.ExpiresAt); until <= 0 || until > deleteTokenTTL {
+		t.Errorf("token expires in %v, want within %v", until, deleteTokenTTL)
+	}
+	return got.ConfirmToken
+}
+
+func withConfirmDeletes(cfg *Config) { cfg.ConfirmDeletes = true }
+
+func TestConfirmedDelete(t *testing.T) {
+	router := newTestRouter(t, withConfirmDeletes, testRecipe("r1", "Soup"), testRecipe("r2", "Stew"))
+
+	token := confirmToken(t, router, "r1")
+	storedRecipe(t, "r1")
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r2?confirm="+token, ""), http.StatusBadRequest)
+	storedRecipe(t, "r2")
+
+	// The token was used up by the attempt on r2.
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r1?confirm="+token, ""), http.StatusBadRequest)
+	token = confirmToken(t, router, "r1")
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r1?confirm="+token, ""), http.StatusOK)
+	if findRecipe("r1") >= 0 {
+		t.Error("r1 not deleted with a valid token")
+	}
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r1?confirm="+token, ""), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r2?confirm=made-up", ""), http.StatusBadRequest)
+}
+
+func TestExpiredConfirmTokenRejected(t *testing.T) {
+	router := newTestRouter(t, withConfirmDeletes, testRecipe("r1", "Soup"))
+
+	token := confirmToken(t, router, "r1")
+	pendingDeletes.mu.Lock()
+	pending := pendingDeletes.tokens[token]
+	pending.expires = time.Now().Add(-time.Second)
+	pendingDeletes.tokens[token] = pending
+	pendingDeletes.mu.Unlock()
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r1?confirm="+token, ""), http.StatusBadRequest)
+	storedRecipe(t, "r1")
+}
+
+func TestDeleteWithoutConfirmation(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r1", ""), http.StatusOK)
+	if findRecipe("r1") >= 0 {
+		t.Error("r1 not deleted in one step with CONFIRM_DELETES off")
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// contentFilter blocks or masks banned words in a recipe's free-text fields.
+// A nil pattern means the filter is off.
+type contentFilter struct {
+	pattern *regexp.Regexp
+	mask    bool
+}
+
+var bannedWords contentFilter
+
+// loadContentFilter enables the filter if cfg asks for it, reading the word
+// list file (one word or phrase per line, # comments).
+func loadContentFilter(cfg ContentFilterConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	f := contentFilter{mask: cfg.Mask}
+	path := cfg.WordsFile
+	words, err := readWordList(path)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("word list %s is empty", path)
+	}
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(w)
+	}
+	f.pattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	bannedWords = f
+	return nil
+}
+
+func readWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var words []string
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		w := strings.TrimSpace(sc.Text())
+		if w == "" || strings.HasPrefix(w, "#") {
+			continue
+		}
+		words = append(words, w)
+	}
+	return words, sc.Err()
+}
+
+// textFields calls fn with a pointer to each free-text field of r and a
+// name for it in error messages.
+func textFields(r *Recipe, fn func(field string, s *string)) {
+	fn("name", &r.Name)
+	fn("yieldText", &r.YieldText)
+	for i := range r.Tags {
+		fn(fmt.Sprintf("tags[%d]", i), &r.Tags[i])
+	}
+	for i := range r.Ingredients {
+		fn(fmt.Sprintf("ingredients[%d]", i), &r.Ingredients[i])
+	}
+	for i := range r.Instructions {
+		fn(fmt.Sprintf("instructions[%d]", i), &r.Instructions[i])
+	}
+	for i := range r.Steps {
+		fn(fmt.Sprintf("steps[%d].text", i), &r.Steps[i].Text)
+	}
+	for i := range r.Videos {
+		fn(fmt.Sprintf("videos[%d].title", i), &r.Videos[i].Title)
+	}
+	for _, lang := range slices.Sorted(maps.Keys(r.Translations)) {
+		t := r.Translations[lang]
+		name := t.Name
+		fn(fmt.Sprintf("translations.%s.name", lang), &name)
+		if name != t.Name {
+			// Only write to the map on change, so read-only callers
+			// such as check never do.
+			t.Name = name
+			r.Translations[lang] = t
+		}
+		for i := range t.Ingredients {
+			fn(fmt.Sprintf("translations.%s.ingredients[%d]", lang, i), &t.Ingredients[i])
+		}
+		for i := range t.Instructions {
+			fn(fmt.Sprintf("translations.%s.instructions[%d]", lang, i), &t.Instructions[i])
+		}
+	}
+}
+
+// maskRecipe replaces each banned word with asterisks in mask mode.
+func (f contentFilter) maskRecipe(r *Recipe) {
+	if f.pattern == nil || !f.mask {
+		return
+	}
+	textFields(r, func(_ string, s *string) {
+		*s = f.pattern.ReplaceAllStringFunc(*s, func(w string) string {
+			return strings.Repeat("*", len([]rune(w)))
+		})
+	})
+}
+
+// check reports the first field containing a banned word in reject mode.
+// The word itself is not echoed back.
+func (f contentFilter) check(r *Recipe) error {
+	if f.pattern == nil || f.mask {
+		return nil
+	}
+	var err error
+	textFields(r, func(field string, s *string) {
+		if err == nil && f.pattern.MatchString(*s) {
+			err = fmt.Errorf("%s contains a blocked word", field)
+		}
+	})
+	return err
+}
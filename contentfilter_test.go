@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// filteredRouter is a router with the content filter on in the given mode,
+// banning "darn" and "heck".
+func filteredRouter(t *testing.T, mask bool) http.Handler {
+	t.Helper()
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	words := writeFixture(t, "# banned\ndarn\n\nheck\n")
+	if err := loadContentFilter(ContentFilterConfig{Enabled: true, Mask: mask, WordsFile: words}); err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+func TestContentFilterRejects(t *testing.T) {
+	router := filteredRouter(t, false)
+
+	body := `{"name":"Stew","ingredients":["2 onions"],"instructions":["Stir the DARN pot."]}`
+	w := serve(router, http.MethodPost, "/recipes", body)
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+	msg := decodeBody[ErrorResponse](t, w).Error
+	if !strings.Contains(msg, "instructions[0]") || strings.Contains(strings.ToLower(msg), "darn") {
+		t.Errorf("error = %q, want the field named and the word withheld", msg)
+	}
+
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/r1", `{"name":"Heck soup","ingredients":["water"],"instructions":["Boil."]}`), http.StatusUnprocessableEntity)
+	if got := storedRecipe(t, "r1").Name; got != "Soup" {
+		t.Errorf("rejected update changed the name to %q", got)
+	}
+
+	// Whole words only: "darning" is not banned.
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", `{"name":"Darning stew","ingredients":["2 onions"],"instructions":["Stir."]}`), http.StatusCreated)
+}
+
+func TestContentFilterMasks(t *testing.T) {
+	router := filteredRouter(t, true)
+
+	body := `{"name":"Darn good stew","ingredients":["2 onions"],"instructions":["Stir the heck out of it."]}`
+	w := serve(router, http.MethodPost, "/recipes", body)
+	expectStatus(t, w, http.StatusCreated)
+	got := decodeBody[Recipe](t, w)
+	if got.Name != "**** good stew" {
+		t.Errorf("name = %q, want the banned word masked", got.Name)
+	}
+	if want := []string{"Stir the **** out of it."}; !slices.Equal(got.Instructions, want) {
+		t.Errorf("instructions = %q, want %q", got.Instructions, want)
+	}
+	if stored := storedRecipe(t, got.ID); stored.Name != got.Name {
+		t.Errorf("stored name = %q, want %q", stored.Name, got.Name)
+	}
+}
+
+func TestContentFilterNeedsWords(t *testing.T) {
+	newTestRouter(t, nil)
+	if err := loadContentFilter(ContentFilterConfig{Enabled: true, WordsFile: writeFixture(t, "# nothing\n")}); err == nil {
+		t.Error("loading an empty word list succeeded")
+	}
+	if _, err := loadConfig(func(k string) string {
+		return map[string]string{"CONTENT_FILTER": "true"}[k]
+	}); err == nil {
+		t.Error("CONTENT_FILTER without CONTENT_FILTER_WORDS was accepted")
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// csvColumns is the header of the CSV export and the columns understood by
+// the import. Lists of strings are joined with csvListSeparator; steps,
+// videos and translations, which have fields of their own, are JSON.
+var csvColumns = []string{
+	"id", "name", "tags", "category", "ingredients", "instructions",
+	"allergens", "equipment", "difficulty", "prepTime", "cookTime",
+	"servings", "yieldText", "publishedAt", "updatedAt",
+	"steps", "videos", "costCents", "currency", "language", "translations",
+}
+
+const (
+	csvListSeparator = "|"
+	maxImportBytes   = 10 << 20
+)
+
+func csvRow(r Recipe) []string {
+	return []string{
+		r.ID,
+		r.Name,
+		strings.Join(r.Tags, csvListSeparator),
+		r.Category,
+		strings.Join(r.Ingredients, csvListSeparator),
+		strings.Join(r.Instructions, csvListSeparator),
+		strings.Join(r.Allergens, csvListSeparator),
+		strings.Join(r.Equipment, csvListSeparator),
+		r.Difficulty,
+		strconv.Itoa(r.PrepTime),
+		strconv.Itoa(r.CookTime),
+		strconv.Itoa(r.Servings),
+		r.YieldText,
+		r.PublishedAt.rfc3339(),
+		r.UpdatedAt.rfc3339(),
+		csvJSON(r.Steps),
+		csvJSON(r.Videos),
+		strconv.Itoa(r.CostCents),
+		r.Currency,
+		r.Language,
+		csvJSON(r.Translations),
+	}
+}
+
+// csvJSON encodes v for a CSV cell, leaving the cell empty when v is empty.
+func csvJSON(v any) string {
+	if reflect.ValueOf(v).Len() == 0 {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// ExportCSVHandler downloads the recipes matching the list filters as CSV,
+// one row per recipe, with every field ImportCSVHandler reads back. The
+// server-managed thumbnail, pin and parent are not exported.
+//
+// @Summary Export recipes as CSV
+// @Tags recipes
+// @Produce text/csv
+// @Param tag query string false "Only recipes with this tag"
+// @Param category query string false "Only recipes in this category"
+// @Param q query string false "Only recipes whose name, tags or ingredients contain this text"
+// @Success 200 {string} string "CSV with a header row"
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/export.csv [get]
+func ExportCSVHandler(c *gin.Context) {
+	list, err := exportedRecipes(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rows := make([][]string, 0, len(list)+1)
+	rows = append(rows, csvColumns)
+	for _, r := range list {
+		rows = append(rows, csvRow(r))
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="recipes.csv"`)
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	w.WriteAll(rows)
+}
+
+// ImportError describes a CSV row that was skipped.
+type ImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ImportResult summarises a CSV import.
+type ImportResult struct {
+	Imported int           `json:"imported"`
+	Errors   []ImportError `json:"errors"`
+}
+
+func csvList(v string) []string {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+	return strings.Split(v, csvListSeparator)
+}
+
+// recipeFromCSV builds a recipe from a row, looking columns up by header
+// name so missing or reordered columns are tolerated. The id and timestamp
+// columns are ignored; imported recipes are always new. Like a created
+// recipe it gets the configured default tags, unless defaultTags is false,
+// and any tags derived from its ingredients.
+func recipeFromCSV(columns map[string]int, row []string, defaultTags bool) (Recipe, error) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	getInt := func(name string) (int, error) {
+		v := strings.TrimSpace(get(name))
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("%s must be an integer", name)
+		}
+		return n, nil
+	}
+
+	r := Recipe{
+		Name:         strings.TrimSpace(get("name")),
+		Tags:         csvList(get("tags")),
+		Category:     get("category"),
+		Ingredients:  csvList(get("ingredients")),
+		Instructions: csvList(get("instructions")),
+		Allergens:    csvList(get("allergens")),
+		Equipment:    csvList(get("equipment")),
+		Difficulty:   get("difficulty"),
+		YieldText:    get("yieldText"),
+	}
+	var err error
+	if r.PrepTime, err = getInt("prepTime"); err != nil {
+		return r, err
+	}
+	if r.CookTime, err = getInt("cookTime"); err != nil {
+		return r, err
+	}
+	if r.Servings, err = getInt("servings"); err != nil {
+		return r, err
+	}
+	if r.CostCents, err = getInt("costCents"); err != nil {
+		return r, err
+	}
+	r.Currency = get("currency")
+	r.Language = get("language")
+	for _, f := range []struct {
+		name string
+		dst  any
+	}{{"steps", &r.Steps}, {"videos", &r.Videos}, {"translations", &r.Translations}} {
+		if v := strings.TrimSpace(get(f.name)); v != "" {
+			if err := json.Unmarshal([]byte(v), f.dst); err != nil {
+				return r, fmt.Errorf("%s must be JSON: %v", f.name, err)
+			}
+		}
+	}
+	if r.Name == "" {
+		return r, fmt.Errorf("name is required")
+	}
+	if defaultTags {
+		r.Tags = append(r.Tags, config.DefaultTags...)
+	}
+	r.Tags = append(r.Tags, autoTags.derive(r)...)
+	normalizeRecipe(&r)
+	if err := validateRecipe(&r); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// csvUpload returns the uploaded CSV: the multipart field "file" if present,
+// otherwise the raw request body.
+func csvUpload(c *gin.Context) (io.ReadCloser, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		return header.Open()
+	}
+	if !hasBody(c.Request) {
+		return nil, errEmptyBody
+	}
+	return c.Request.Body, nil
+}
+
+// ImportCSVHandler creates a recipe from each row of an uploaded CSV in the
+// export format, tagged as NewRecipeHandler would tag it. Rows that fail to
+// parse or validate are skipped and reported by row number, counting the
+// first data row as 1.
+//
+// @Summary Import recipes from CSV
+// @Tags recipes
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Produce json
+// @Param file formData file false "CSV file (or send it as the body)"
+// @Param noDefaultTags query bool false "Skip the configured default tags"
+// @Security ApiKeyAuth
+// @Success 200 {object} ImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /recipes/import.csv [post]
+func ImportCSVHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	f, err := csvUpload(c)
+	if err != nil {
+		status := http.StatusBadRequest
+		if _, tooBig := err.(*http.MaxBytesError); tooBig {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": "csv file is required: " + err.Error()})
+		return
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reading csv header: " + err.Error()})
+		return
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "csv header has no name column"})
+		return
+	}
+
+	result := ImportResult{Errors: make([]ImportError, 0)}
+	var parsed []Recipe
+	var rows []int
+	for n := 1; ; n++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Row: n, Message: err.Error()})
+			continue
+		}
+		if len(row) > len(header) {
+			result.Errors = append(result.Errors, ImportError{Row: n, Message: fmt.Sprintf("expected %d fields, got %d", len(header), len(row))})
+			continue
+		}
+		r, err := recipeFromCSV(columns, row, c.Query("noDefaultTags") != "true")
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Row: n, Message: err.Error()})
+			continue
+		}
+		parsed = append(parsed, r)
+		rows = append(rows, n)
+	}
+
+	now := time.Now()
+	var created, evicted []string
+	recipesMu.Lock()
+	for k, r := range parsed {
+		gone, ok := config.Capacity.makeRoom()
+		if !ok {
+			result.Errors = append(result.Errors, ImportError{Row: rows[k], Message: fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)})
+			continue
+		}
+		if gone != "" {
+			evicted = append(evicted, gone)
+		}
+		r.ID = newRecipeID()
+		r.PublishedAt = Timestamp{now}
+		r.UpdatedAt = Timestamp{now}
+		insertRecipe(r)
+		created = append(created, r.ID)
+	}
+	recipesMu.Unlock()
+
+	for _, id := range evicted {
+		auditor.record(c, "evict", id)
+	}
+	for _, id := range created {
+		auditor.record(c, "create", id)
+	}
+	result.Imported = len(created)
+	c.JSON(http.StatusOK, result)
+}
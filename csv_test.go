@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const importCSV = `name,tags,category,ingredients,instructions
+Roast chicken,Dinner|Sunday,main,1 chicken|2 lemons,Roast.|Rest.
+Mystery,,brunch,eggs,Whisk.
+Garlic bread,,side,bread|garlic,Toast.
+`
+
+func TestImportCSVPartial(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.DefaultTags = []string{"imported"}
+	})
+	autoTags = autoTagger{rules: []autoTagRule{{phrase: " chicken ", tags: []string{"poultry"}}}}
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/import.csv", importCSV, "Content-Type", "text/csv"), http.StatusUnauthorized)
+
+	w := serve(router, http.MethodPost, "/recipes/import.csv", importCSV, "Content-Type", "text/csv", "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	result := decodeBody[ImportResult](t, w)
+	if result.Imported != 2 || len(result.Errors) != 1 || result.Errors[0].Row != 2 {
+		t.Fatalf("result = %+v, want 2 imported and row 2 rejected", result)
+	}
+
+	byName := make(map[string]Recipe)
+	recipesMu.RLock()
+	for _, r := range recipes {
+		byName[r.Name] = r
+	}
+	recipesMu.RUnlock()
+	chicken := byName["Roast chicken"]
+	if chicken.ID == "" || chicken.PublishedAt.IsZero() {
+		t.Errorf("imported recipe %+v has no ID or publication time", chicken)
+	}
+	if want := []string{"dinner", "sunday", "imported", "poultry"}; !slices.Equal(chicken.Tags, want) {
+		t.Errorf("tags = %q, want %q", chicken.Tags, want)
+	}
+	if want := []string{"1 chicken", "2 lemons"}; !slices.Equal(chicken.Ingredients, want) {
+		t.Errorf("ingredients = %q, want %q", chicken.Ingredients, want)
+	}
+	if got := byName["Garlic bread"].Tags; !slices.Equal(got, []string{"imported"}) {
+		t.Errorf("garlic bread tags = %q, want [imported]", got)
+	}
+}
+
+func TestImportCSVMultipartWithoutDefaultTags(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.DefaultTags = []string{"imported"}
+	})
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "recipes.csv")
+	part.Write([]byte(importCSV))
+	mw.Close()
+
+	w := serve(router, http.MethodPost, "/recipes/import.csv?noDefaultTags=true", body.String(),
+		"Content-Type", mw.FormDataContentType(), "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[ImportResult](t, w).Imported; got != 2 {
+		t.Fatalf("imported %d, want 2", got)
+	}
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	for _, r := range recipes {
+		if slices.Contains(r.Tags, "imported") {
+			t.Errorf("%s got default tags despite noDefaultTags", r.Name)
+		}
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	r := testRecipe("r1", "Roast", "dinner")
+	r.Steps = []Step{{Text: "Roast.", DurationSeconds: 3600, TemperatureC: 200}}
+	r.Instructions = []string{"Roast."}
+	r.Videos = []Video{{Title: "How to", URL: "https://example.com/roast"}}
+	r.CostCents, r.Currency = 1250, "EUR"
+	r.Language = "en"
+	r.Translations = map[string]Translation{"fr": {Name: "Rôti"}}
+	router := newTestRouter(t, withUsers, r)
+
+	w := serve(router, http.MethodGet, "/recipes/export.csv", "")
+	expectStatus(t, w, http.StatusOK)
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil || len(rows) != 2 || !slices.Equal(rows[0], csvColumns) {
+		t.Fatalf("export = %q, %v; want the header and one row", rows, err)
+	}
+
+	router = newTestRouter(t, withUsers)
+	w = serve(router, http.MethodPost, "/recipes/import.csv?noDefaultTags=true", w.Body.String(), "Content-Type", "text/csv", "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	recipesMu.RLock()
+	imported := recipes[0]
+	recipesMu.RUnlock()
+	if !slices.Equal(imported.Steps, r.Steps) || !slices.Equal(imported.Videos, r.Videos) ||
+		imported.CostCents != 1250 || imported.Currency != "EUR" || imported.Language != "en" ||
+		imported.Translations["fr"].Name != "Rôti" {
+		t.Errorf("imported %+v, want the exported fields back", imported)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DifficultyEstimate is the response of the estimate-difficulty endpoint.
+type DifficultyEstimate struct {
+	ID           string  `json:"id"`
+	Difficulty   string  `json:"difficulty"`
+	Score        float64 `json:"score"`
+	Ingredients  int     `json:"ingredients"`
+	Steps        int     `json:"steps"`
+	TotalMinutes int     `json:"totalMinutes"`
+}
+
+// Difficulty score thresholds: scores below easyBelow are easy, below
+// mediumBelow medium, and anything else hard.
+const (
+	easyBelow   = 15
+	mediumBelow = 30
+)
+
+// estimateDifficulty scores a recipe as
+//
+//	ingredients + 2*steps + (prepTime+cookTime)/10
+//
+// so a step counts twice as much as an ingredient and every ten minutes of
+// total time adds one point.
+func estimateDifficulty(r Recipe) DifficultyEstimate {
+	e := DifficultyEstimate{
+		ID:           r.ID,
+		Ingredients:  len(r.Ingredients),
+		Steps:        len(r.Instructions),
+		TotalMinutes: r.PrepTime + r.CookTime,
+	}
+	e.Score = float64(e.Ingredients) + 2*float64(e.Steps) + float64(e.TotalMinutes)/10
+	switch {
+	case e.Score < easyBelow:
+		e.Difficulty = "easy"
+	case e.Score < mediumBelow:
+		e.Difficulty = "medium"
+	default:
+		e.Difficulty = "hard"
+	}
+	return e
+}
+
+// EstimateDifficultyHandler derives a difficulty from a recipe's size and
+// duration without changing the stored recipe.
+//
+// @Summary Estimate a recipe's difficulty
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} DifficultyEstimate
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/estimate-difficulty [get]
+func EstimateDifficultyHandler(c *gin.Context) {
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	c.JSON(http.StatusOK, estimateDifficulty(recipes[i]))
+}
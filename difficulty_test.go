@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// craftedRecipe has the given number of ingredients and steps and total
+// time in minutes.
+func craftedRecipe(id string, ingredients, steps, minutes int) Recipe {
+	r := Recipe{ID: id, Name: id, PrepTime: minutes}
+	for i := range ingredients {
+		r.Ingredients = append(r.Ingredients, fmt.Sprintf("ingredient %d", i))
+	}
+	for i := range steps {
+		r.Instructions = append(r.Instructions, fmt.Sprintf("step %d", i))
+	}
+	return r
+}
+
+func TestEstimateDifficultyThresholds(t *testing.T) {
+	for _, tc := range []struct {
+		recipe Recipe
+		score  float64
+		want   string
+	}{
+		{craftedRecipe("tiny", 2, 1, 10), 5, "easy"},
+		{craftedRecipe("justEasy", 4, 5, 9), 14.9, "easy"},
+		{craftedRecipe("easyEdge", 5, 5, 0), 15, "medium"},
+		{craftedRecipe("justMedium", 9, 10, 9), 29.9, "medium"},
+		{craftedRecipe("mediumEdge", 10, 10, 0), 30, "hard"},
+		{craftedRecipe("long", 3, 2, 240), 31, "hard"},
+	} {
+		got := estimateDifficulty(tc.recipe)
+		if got.Score != tc.score || got.Difficulty != tc.want {
+			t.Errorf("%s: score %v (%s), want %v (%s)", tc.recipe.ID, got.Score, got.Difficulty, tc.score, tc.want)
+		}
+	}
+}
+
+func TestEstimateDifficultyDoesNotPersist(t *testing.T) {
+	router := newTestRouter(t, nil, craftedRecipe("r1", 10, 10, 60))
+
+	w := serve(router, http.MethodGet, "/recipe/r1/estimate-difficulty", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[DifficultyEstimate](t, w).Difficulty; got != "hard" {
+		t.Errorf("difficulty = %q, want hard", got)
+	}
+	if got := storedRecipe(t, "r1").Difficulty; got != "" {
+		t.Errorf("stored difficulty = %q, want it unchanged", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/estimate-difficulty", ""), http.StatusNotFound)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultDuplicateThreshold is the ingredient similarity at which two
+// differently named recipes count as likely duplicates when ?threshold= is
+// not given. It is stricter than defaultSimilarThreshold since variations
+// of a dish commonly share half their ingredients.
+const defaultDuplicateThreshold = 0.8
+
+// DuplicateCluster is a group of recipes that are likely duplicates of one
+// another, in store order.
+type DuplicateCluster struct {
+	IDs   []string `json:"ids"`
+	Names []string `json:"names"`
+}
+
+// duplicateName reduces a recipe name for duplicate detection: folded like
+// search text, with punctuation dropped, spaces collapsed and a trailing
+// "(copy)" left by cloning removed.
+func duplicateName(name string) string {
+	name = strings.TrimSuffix(strings.TrimSpace(foldText(name)), "(copy)")
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// duplicateClusters groups list into clusters of likely duplicates: two
+// recipes are linked when their names match under duplicateName or their
+// ingredient similarity is at least threshold, and clusters are the
+// connected groups of two or more. Clusters are ordered by their first
+// recipe in list.
+func duplicateClusters(list []Recipe, threshold float64) []DuplicateCluster {
+	parent := make([]int, len(list))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := root(i), root(j)
+		if ri < rj {
+			parent[rj] = ri
+		} else if rj < ri {
+			parent[ri] = rj
+		}
+	}
+
+	byName := make(map[string]int)
+	sets := make([]map[string]bool, len(list))
+	for i, r := range list {
+		if name := duplicateName(r.Name); name != "" {
+			if j, seen := byName[name]; seen {
+				union(i, j)
+			} else {
+				byName[name] = i
+			}
+		}
+		sets[i] = ingredientSet(r)
+	}
+	for i := range list {
+		for j := i + 1; j < len(list); j++ {
+			if s := jaccard(sets[i], sets[j]); s > 0 && s >= threshold {
+				union(i, j)
+			}
+		}
+	}
+
+	groups := make(map[int]*DuplicateCluster)
+	var order []int
+	for i, r := range list {
+		g := groups[root(i)]
+		if g == nil {
+			g = &DuplicateCluster{}
+			groups[root(i)] = g
+			order = append(order, root(i))
+		}
+		g.IDs = append(g.IDs, r.ID)
+		g.Names = append(g.Names, r.Name)
+	}
+	out := []DuplicateCluster{}
+	for _, k := range order {
+		if g := groups[k]; len(g.IDs) > 1 {
+			out = append(out, *g)
+		}
+	}
+	return out
+}
+
+// DuplicateRecipesHandler lists clusters of likely duplicate recipes across
+// the whole collection, to help clean up imported data. Comparing every
+// pair of recipes is quadratic, which is fine for the in-memory store.
+//
+// @Summary List likely duplicate recipes
+// @Tags recipes
+// @Produce json
+// @Param threshold query number false "Minimum ingredient similarity between 0 and 1 for differently named recipes (default 0.8)"
+// @Success 200 {array} DuplicateCluster
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/duplicates [get]
+func DuplicateRecipesHandler(c *gin.Context) {
+	threshold := defaultDuplicateThreshold
+	if v := c.Query("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || t > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a number between 0 and 1"})
+			return
+		}
+		threshold = t
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	c.JSON(http.StatusOK, duplicateClusters(recipes, threshold))
+}
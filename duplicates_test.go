@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestDuplicateClusters(t *testing.T) {
+	withIngredients := func(id, name string, ingredients ...string) Recipe {
+		r := testRecipe(id, name)
+		r.Ingredients = ingredients
+		return r
+	}
+	router := newTestRouter(t, nil,
+		withIngredients("a", "Banana Bread", "3 bananas", "2 cups flour", "sugar", "2 eggs"),
+		withIngredients("b", "banana  bread!", "ripe banana"),
+		withIngredients("c", "Bánana Bread (copy)", "walnuts"),
+		withIngredients("d", "Mom's loaf", "bananas", "flour", "sugar", "eggs", "butter"),
+		withIngredients("e", "Pancakes", "flour", "eggs", "milk"),
+		withIngredients("f", "Tomato soup", "tomatoes", "water"),
+		withIngredients("g", "Gazpacho", "tomatoes", "water", "cucumber"),
+	)
+
+	for _, tc := range []struct {
+		query string
+		want  [][]string
+	}{
+		{"", [][]string{{"a", "b", "c", "d"}}},
+		{"?threshold=0.6", [][]string{{"a", "b", "c", "d"}, {"f", "g"}}},
+		{"?threshold=1", [][]string{{"a", "b", "c"}}},
+	} {
+		w := serve(router, http.MethodGet, "/recipes/duplicates"+tc.query, "")
+		expectStatus(t, w, http.StatusOK)
+		got := decodeBody[[]DuplicateCluster](t, w)
+		if !slices.EqualFunc(got, tc.want, func(c DuplicateCluster, ids []string) bool {
+			return slices.Equal(c.IDs, ids) && len(c.Names) == len(ids)
+		}) {
+			t.Errorf("%s: clusters = %+v, want %q", tc.query, got, tc.want)
+		}
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/duplicates?threshold=2", ""), http.StatusBadRequest)
+}
+
+func TestNoDuplicates(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("a", "Soup"))
+
+	w := serve(router, http.MethodGet, "/recipes/duplicates", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := w.Body.String(); got != "[]" {
+		t.Errorf("body = %s, want []", got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorPage is the page shown to browsers in place of an ErrorResponse.
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.Title}}</title>
+<style>body{font-family:system-ui,sans-serif;max-width:40rem;margin:4rem auto;padding:0 1rem;color:#222}h1{font-size:1.5rem}p{color:#555}</style>
+</head>
+<body>
+<h1>{{.Status}} {{.Title}}</h1>
+<p>{{.Message}}</p>
+</body>
+</html>
+`))
+
+// prefersHTML reports whether the client ranks text/html ahead of JSON in
+// its Accept header, as browsers do. A missing Accept header or */* gets
+// JSON.
+func prefersHTML(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+}
+
+// renderErrorPage returns the HTML page for an error response with status
+// and the JSON body the handler wrote. ok is false when body is neither
+// empty nor an ErrorResponse, so it is passed on as it is.
+func renderErrorPage(status int, body []byte) (page []byte, ok bool) {
+	message := http.StatusText(status)
+	if len(body) > 0 {
+		var e ErrorResponse
+		if err := json.Unmarshal(body, &e); err != nil || e.Error == "" {
+			return nil, false
+		}
+		message = e.Error
+	}
+	var b bytes.Buffer
+	err := errorPage.Execute(&b, struct {
+		Status         int
+		Title, Message string
+	}{status, http.StatusText(status), message})
+	if err != nil {
+		return nil, false
+	}
+	return b.Bytes(), true
+}
+
+// errorPageWriter passes a response straight through unless its status is
+// an error, whose body it holds back so HTMLErrorMiddleware can replace it.
+// Successful downloads and long polls are streamed as usual.
+type errorPageWriter struct {
+	gin.ResponseWriter
+	held   bool
+	status int
+	body   bytes.Buffer
+}
+
+func (w *errorPageWriter) WriteHeader(code int) {
+	if code >= 400 && !w.ResponseWriter.Written() {
+		w.held, w.status = true, code
+		return
+	}
+	if !w.held {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *errorPageWriter) WriteHeaderNow() {
+	if !w.held {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *errorPageWriter) Status() int {
+	if w.held {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *errorPageWriter) Write(b []byte) (int, error) {
+	if w.held {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *errorPageWriter) WriteString(s string) (int, error) {
+	if w.held {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *errorPageWriter) Flush() {
+	if !w.held {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend
+// a long poll's write deadline.
+func (w *errorPageWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// HTMLErrorMiddleware renders error responses as a minimal HTML page for
+// clients that prefer text/html, so a browser following a bad link sees a
+// page rather than raw JSON. Only error responses are held back to be
+// rewritten; successful responses, and every response to API clients, are
+// written through unchanged as the handler produces them.
+func HTMLErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+		if !prefersHTML(c) {
+			c.Next()
+			return
+		}
+
+		orig := c.Writer
+		w := &errorPageWriter{ResponseWriter: orig}
+		c.Writer = w
+		c.Next()
+		c.Writer = orig
+		if !w.held {
+			return
+		}
+
+		body := w.body.Bytes()
+		h := orig.Header()
+		if c.Request.Method != http.MethodHead &&
+			(len(body) == 0 || strings.Contains(h.Get("Content-Type"), "json")) {
+			if page, ok := renderErrorPage(w.status, body); ok {
+				body = page
+				h.Set("Content-Type", "text/html; charset=utf-8")
+				h.Set("Content-Length", strconv.Itoa(len(body)))
+				h.Del("ETag")
+			}
+		}
+		orig.WriteHeader(w.status)
+		orig.Write(body)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestErrorPageForBrowsers(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	router.GET("/panic", func(*gin.Context) { panic("boom") })
+
+	for _, tc := range []struct {
+		target  string
+		status  int
+		message string
+	}{
+		{"/nowhere", http.StatusNotFound, "route not found"},
+		{"/recipe/missing", http.StatusNotFound, "Recipe not found"},
+		{"/recipes?sort=cost", http.StatusBadRequest, "sort must be one of name, publishedAt, updatedAt"},
+		{"/panic", http.StatusInternalServerError, "internal server error"},
+	} {
+		w := serve(router, http.MethodGet, tc.target, "", "Accept", browserAccept)
+		expectStatus(t, w, tc.status)
+		if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+			t.Errorf("%s: Content-Type = %q, want text/html", tc.target, ct)
+		}
+		body := w.Body.String()
+		heading := fmt.Sprintf("<h1>%d %s</h1>", tc.status, http.StatusText(tc.status))
+		if !strings.Contains(body, heading) || !strings.Contains(body, "<p>"+tc.message+"</p>") {
+			t.Errorf("%s: page %s does not show %s and %q", tc.target, body, heading, tc.message)
+		}
+		if !strings.Contains(w.Header().Get("Vary"), "Accept") {
+			t.Errorf("%s: Vary = %q, want Accept", tc.target, w.Header().Get("Vary"))
+		}
+	}
+
+	// Successful responses are unchanged for browsers.
+	w := serve(router, http.MethodGet, "/recipe/r1", "", "Accept", browserAccept)
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[Recipe](t, w); got.ID != "r1" {
+		t.Errorf("browser got %+v, want the recipe as JSON", got)
+	}
+}
+
+func TestErrorJSONForAPIClients(t *testing.T) {
+	router := newTestRouter(t, nil)
+	for _, accept := range []string{"", "*/*", "application/json", "application/json, text/html;q=0.5"} {
+		w := serve(router, http.MethodGet, "/recipe/missing", "", "Accept", accept)
+		expectStatus(t, w, http.StatusNotFound)
+		if got := decodeBody[ErrorResponse](t, w).Error; got != "Recipe not found" {
+			t.Errorf("Accept %q: error = %q, want the JSON error", accept, got)
+		}
+	}
+
+	router = newTestRouter(t, func(cfg *Config) { cfg.HTMLErrors = false })
+	w := serve(router, http.MethodGet, "/recipe/missing", "", "Accept", browserAccept)
+	expectStatus(t, w, http.StatusNotFound)
+	if got := decodeBody[ErrorResponse](t, w).Error; got != "Recipe not found" {
+		t.Errorf("with HTML errors off, error = %q, want the JSON error", got)
+	}
+}
+
+func TestErrorPageEscapesMessage(t *testing.T) {
+	page, ok := renderErrorPage(http.StatusBadRequest, []byte(`{"error":"bad <script>"}`))
+	if !ok || !strings.Contains(string(page), "<p>bad &lt;script&gt;</p>") {
+		t.Errorf("page = %s, %v; want the message escaped", page, ok)
+	}
+	if _, ok := renderErrorPage(http.StatusBadRequest, []byte(`{"errors":["a","b"]}`)); ok {
+		t.Error("rendered a body that is not an ErrorResponse")
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagFor returns a strong ETag derived from a response body.
+func etagFor(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// writeJSONBody writes an already-marshaled JSON body with ETag and
+// Content-Length headers. For HEAD requests only the headers are sent.
+func writeJSONBody(c *gin.Context, status int, body []byte) {
+	h := c.Writer.Header()
+	h.Set("ETag", etagFor(body))
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	if c.Request.Method == http.MethodHead {
+		h.Set("Content-Type", "application/json; charset=utf-8")
+		c.Status(status)
+		return
+	}
+	c.Data(status, "application/json; charset=utf-8", body)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestHeadMatchesGetHeaders(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	for _, target := range []string{"/recipe/r1", "/recipes"} {
+		get := serve(router, http.MethodGet, target, "")
+		expectStatus(t, get, http.StatusOK)
+		head := serve(router, http.MethodHead, target, "")
+		expectStatus(t, head, http.StatusOK)
+
+		if head.Body.Len() != 0 {
+			t.Errorf("HEAD %s sent a %d-byte body", target, head.Body.Len())
+		}
+		if etag := head.Header().Get("ETag"); etag == "" || etag != get.Header().Get("ETag") {
+			t.Errorf("HEAD %s ETag = %q, GET sent %q", target, etag, get.Header().Get("ETag"))
+		}
+		if got, want := head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+			t.Errorf("HEAD %s Content-Length = %q, want %s", target, got, want)
+		}
+		if got := head.Header().Get("Content-Type"); got != get.Header().Get("Content-Type") {
+			t.Errorf("HEAD %s Content-Type = %q, GET sent %q", target, got, get.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestHeadMissingRecipe(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodHead, "/recipe/missing", "")
+	expectStatus(t, w, http.StatusNotFound)
+	if w.Body.Len() != 0 {
+		t.Errorf("HEAD of a missing recipe sent body %q", w.Body.String())
+	}
+}
+
+func TestHeadDoesNotCountViews(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	serve(router, http.MethodHead, "/recipe/r1", "")
+	if n := recipeViews.counts(viewRetention, clock())["r1"]; n != 0 {
+		t.Errorf("HEAD counted %d views", n)
+	}
+}
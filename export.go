@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportedRecipes returns the recipes matching the list filters in the
+// query, in store order, for the export endpoints.
+func exportedRecipes(c *gin.Context) ([]Recipe, error) {
+	filter, err := parseListFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	out := make([]Recipe, 0, len(recipes))
+	for _, r := range recipes {
+		if filter.match(r) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// ExportJSONHandler downloads the recipes matching the list filters as a
+// JSON array.
+//
+// @Summary Export recipes as JSON
+// @Tags recipes
+// @Produce json
+// @Param tag query string false "Only recipes with this tag"
+// @Param category query string false "Only recipes in this category"
+// @Param q query string false "Only recipes whose name, tags or ingredients contain this text"
+// @Success 200 {array} Recipe
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/export.json [get]
+func ExportJSONHandler(c *gin.Context) {
+	list, err := exportedRecipes(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="recipes.json"`)
+	c.JSON(http.StatusOK, freshList(list))
+}
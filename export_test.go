@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func exportSeed() []Recipe {
+	soup := testRecipe("soup", "Tomato soup", "vegan", "quick")
+	soup.Category = "starter"
+	stew := testRecipe("stew", "Beef stew", "winter")
+	stew.Category = "main"
+	curry := testRecipe("curry", "Tofu curry", "vegan")
+	curry.Category = "main"
+	curry.Ingredients = []string{"200 g tofu", "1 tin tomatoes"}
+	return []Recipe{soup, stew, curry}
+}
+
+func TestExportJSONFiltered(t *testing.T) {
+	router := newTestRouter(t, nil, exportSeed()...)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"soup", "stew", "curry"}},
+		{"tag=vegan", []string{"soup", "curry"}},
+		{"category=main", []string{"stew", "curry"}},
+		{"q=tomato", []string{"soup", "curry"}},
+		{"tag=vegan&category=main", []string{"curry"}},
+		{"tag=dessert", []string{}},
+	} {
+		w := serve(router, http.MethodGet, "/recipes/export.json?"+tc.query, "")
+		expectStatus(t, w, http.StatusOK)
+		if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "recipes.json") {
+			t.Errorf("%s: Content-Disposition = %q", tc.query, cd)
+		}
+		got := make([]string, 0)
+		for _, r := range decodeBody[[]Recipe](t, w) {
+			got = append(got, r.ID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: exported %q, want %q", tc.query, got, tc.want)
+		}
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/export.json?maxCost=-1&currency=USD", ""), http.StatusBadRequest)
+}
+
+func TestExportCSVFiltered(t *testing.T) {
+	router := newTestRouter(t, nil, exportSeed()...)
+
+	w := serve(router, http.MethodGet, "/recipes/export.csv?tag=vegan&q="+url.QueryEscape("tofu"), "")
+	expectStatus(t, w, http.StatusOK)
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := slices.Index(csvColumns, "name")
+	if len(rows) != 2 || rows[1][name] != "Tofu curry" {
+		t.Errorf("rows = %q, want the header and Tofu curry", rows)
+	}
+}
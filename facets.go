@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseFacet splits a faceted tag of the form "facet:value", such as
+// "cuisine:italian". ok is false for a plain tag, including one with an
+// empty facet or value.
+func parseFacet(tag string) (facet, value string, ok bool) {
+	facet, value, ok = strings.Cut(tag, ":")
+	facet, value = strings.TrimSpace(facet), strings.TrimSpace(value)
+	if !ok || facet == "" || value == "" {
+		return "", "", false
+	}
+	return facet, value, true
+}
+
+// normalizeTags is normalizeList for tags, which also drops the spaces
+// around a facet's colon so "Cuisine : Italian" is stored as
+// "cuisine:italian".
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		if facet, value, ok := parseFacet(t); ok {
+			t = facet + ":" + value
+		}
+		out[i] = t
+	}
+	return normalizeList(out)
+}
+
+// facetTerm matches recipes with a tag in facet, and with value too when
+// value is set. It plugs into tag expression search like tagTerm.
+type facetTerm struct{ facet, value string }
+
+func (f facetTerm) match(tag string) bool {
+	facet, value, ok := parseFacet(foldText(tag))
+	return ok && facet == f.facet && (f.value == "" || value == f.value)
+}
+
+func (f facetTerm) eval(r Recipe) bool {
+	for _, t := range r.Tags {
+		if f.match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f facetTerm) ids(idx *recipeIndex) idSet {
+	if f.value != "" {
+		return idx.withTag(f.facet + ":" + f.value)
+	}
+	out := make(idSet)
+	for tag, set := range idx.tags {
+		if f.match(tag) {
+			for id := range set {
+				out[id] = struct{}{}
+			}
+		}
+	}
+	return out
+}
+
+// newFacetTerm builds a facetTerm from ?facet= and ?value=, folded like
+// indexed tags.
+func newFacetTerm(facet, value string) facetTerm {
+	return facetTerm{facet: foldText(strings.TrimSpace(facet)), value: foldText(strings.TrimSpace(value))}
+}
+
+// FacetValue is one value of a facet and how many recipes carry it.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facet is a tag facet with its values, most used first.
+type Facet struct {
+	Name   string       `json:"name"`
+	Values []FacetValue `json:"values"`
+}
+
+// FacetsHandler lists the facets used in faceted tags ("facet:value"), each
+// with its values and the number of recipes carrying them. Plain tags are
+// not included.
+//
+// @Summary List tag facets
+// @Tags search
+// @Produce json
+// @Success 200 {array} Facet
+// @Router /recipes/facets [get]
+func FacetsHandler(c *gin.Context) {
+	counts := make(map[string]map[string]int)
+	recipesMu.RLock()
+	for _, r := range recipes {
+		for _, t := range r.Tags {
+			facet, value, ok := parseFacet(t)
+			if !ok {
+				continue
+			}
+			if counts[facet] == nil {
+				counts[facet] = make(map[string]int)
+			}
+			counts[facet][value]++
+		}
+	}
+	recipesMu.RUnlock()
+
+	out := make([]Facet, 0, len(counts))
+	for name, values := range counts {
+		f := Facet{Name: name, Values: make([]FacetValue, 0, len(values))}
+		for v, n := range values {
+			f.Values = append(f.Values, FacetValue{Value: v, Count: n})
+		}
+		sort.Slice(f.Values, func(i, j int) bool {
+			if f.Values[i].Count != f.Values[j].Count {
+				return f.Values[i].Count > f.Values[j].Count
+			}
+			return f.Values[i].Value < f.Values[j].Value
+		})
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	c.JSON(http.StatusOK, out)
+}
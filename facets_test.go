@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestParseFacet(t *testing.T) {
+	for _, tc := range []struct {
+		tag, facet, value string
+		ok                bool
+	}{
+		{"cuisine:italian", "cuisine", "italian", true},
+		{" diet : vegan ", "diet", "vegan", true},
+		{"time:30:min", "time", "30:min", true},
+		{"vegan", "", "", false},
+		{":italian", "", "", false},
+		{"cuisine:", "", "", false},
+	} {
+		facet, value, ok := parseFacet(tc.tag)
+		if facet != tc.facet || value != tc.value || ok != tc.ok {
+			t.Errorf("parseFacet(%q) = %q, %q, %v; want %q, %q, %v", tc.tag, facet, value, ok, tc.facet, tc.value, tc.ok)
+		}
+	}
+}
+
+// facetSeed is recipes tagged with cuisine and course facets plus a plain
+// tag.
+func facetSeed() []Recipe {
+	return []Recipe{
+		testRecipe("pizza", "Pizza", "cuisine:italian", "course:main", "quick"),
+		testRecipe("risotto", "Risotto", "cuisine:italian", "course:main"),
+		testRecipe("tiramisu", "Tiramisu", "cuisine:italian", "course:dessert"),
+		testRecipe("tacos", "Tacos", "cuisine:mexican", "quick"),
+		testRecipe("toast", "Toast", "quick"),
+	}
+}
+
+func TestFacetSearch(t *testing.T) {
+	router := newTestRouter(t, nil, facetSeed()...)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"facet=cuisine&value=italian", []string{"pizza", "risotto", "tiramisu"}},
+		{"facet=Cuisine&value=MEXICAN", []string{"tacos"}},
+		{"facet=course", []string{"pizza", "risotto", "tiramisu"}},
+		{"facet=course&value=dessert", []string{"tiramisu"}},
+		{"tag=quick", []string{"pizza", "tacos", "toast"}},
+		{"facet=cuisine&value=french", []string{}},
+	} {
+		got := listIDs(t, router, "/recipes/search?sort=name&order=asc&"+tc.query)
+		want := slices.Clone(tc.want)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %q, want %q", tc.query, got, want)
+		}
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/search?value=italian", ""), http.StatusBadRequest)
+}
+
+func TestFacetedTagsNormalizedOnCreate(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes?noDefaultTags=true",
+		`{"name":"Paella","tags":["Cuisine : Spanish","quick"],"ingredients":["rice"],"instructions":["Cook."]}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got, want := decodeBody[Recipe](t, w).Tags, []string{"cuisine:spanish", "quick"}; !slices.Equal(got, want) {
+		t.Errorf("tags = %q, want %q", got, want)
+	}
+}
+
+func TestFacetsListing(t *testing.T) {
+	router := newTestRouter(t, nil, facetSeed()...)
+
+	w := serve(router, http.MethodGet, "/recipes/facets", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[[]Facet](t, w)
+	want := []Facet{
+		{Name: "course", Values: []FacetValue{{"main", 2}, {"dessert", 1}}},
+		{Name: "cuisine", Values: []FacetValue{{"italian", 3}, {"mexican", 1}}},
+	}
+	if !slices.EqualFunc(got, want, func(a, b Facet) bool {
+		return a.Name == b.Name && slices.Equal(a.Values, b.Values)
+	}) {
+		t.Errorf("facets = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// favoriteStore records which recipes each user has favorited and when.
+type favoriteStore struct {
+	mu     sync.Mutex
+	byUser map[string]map[string]time.Time
+}
+
+var favorites = &favoriteStore{byUser: make(map[string]map[string]time.Time)}
+
+func (f *favoriteStore) add(user, id string, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	set := f.byUser[user]
+	if set == nil {
+		set = make(map[string]time.Time)
+		f.byUser[user] = set
+	}
+	if _, ok := set[id]; !ok {
+		set[id] = at
+	}
+}
+
+func (f *favoriteStore) remove(user, id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.byUser[user], id)
+}
+
+// ids returns the recipes user has favorited, most recent first.
+func (f *favoriteStore) ids(user string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	set := f.byUser[user]
+	out := make([]string, 0, len(set))
+	for id := range set {
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return set[out[i]].After(set[out[j]]) })
+	return out
+}
+
+// favoriteTally is how many users favorited a recipe and when it was last
+// favorited.
+type favoriteTally struct {
+	count int
+	last  time.Time
+}
+
+func (f *favoriteStore) tallies() map[string]favoriteTally {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]favoriteTally)
+	for _, set := range f.byUser {
+		for id, at := range set {
+			t := out[id]
+			t.count++
+			if at.After(t.last) {
+				t.last = at
+			}
+			out[id] = t
+		}
+	}
+	return out
+}
+
+// AddFavoriteHandler adds a recipe to the caller's favorites.
+//
+// @Summary Favorite a recipe
+// @Tags favorites
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/favorite [post]
+func AddFavoriteHandler(c *gin.Context) {
+	id := c.Param("id")
+	recipesMu.RLock()
+	found := findRecipe(id) >= 0
+	recipesMu.RUnlock()
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	user, _ := currentUser(c)
+	favorites.add(user, id, time.Now())
+	c.JSON(http.StatusOK, gin.H{"message": "Recipe added to favorites"})
+}
+
+// RemoveFavoriteHandler removes a recipe from the caller's favorites.
+//
+// @Summary Unfavorite a recipe
+// @Tags favorites
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} ErrorResponse
+// @Router /recipe/{id}/favorite [delete]
+func RemoveFavoriteHandler(c *gin.Context) {
+	user, _ := currentUser(c)
+	favorites.remove(user, c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Recipe removed from favorites"})
+}
+
+// ListFavoritesHandler returns the caller's favorite recipes, most recently
+// favorited first.
+//
+// @Summary List my favorites
+// @Tags favorites
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} Recipe
+// @Failure 401 {object} ErrorResponse
+// @Router /recipes/favorites [get]
+func ListFavoritesHandler(c *gin.Context) {
+	user, _ := currentUser(c)
+	ids := favorites.ids(user)
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	out := make([]Recipe, 0, len(ids))
+	for _, id := range ids {
+		if i := findRecipe(id); i >= 0 {
+			out = append(out, fresh(recipes[i]))
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// FavoritedRecipe is a recipe with the number of users who favorited it.
+type FavoritedRecipe struct {
+	Recipe
+	Favorites int `json:"favorites"`
+}
+
+// MostFavoritedHandler ranks recipes by how many users favorited them. Ties
+// go to the recipe favorited most recently.
+//
+// @Summary Most favorited recipes
+// @Tags favorites
+// @Produce json
+// @Param limit query int false "Maximum results (default 10)"
+// @Success 200 {array} FavoritedRecipe
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/most-favorited [get]
+func MostFavoritedHandler(c *gin.Context) {
+	limit := 10
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = min(n, maxPageLimit)
+	}
+
+	tallies := favorites.tallies()
+	recipesMu.RLock()
+	out := make([]FavoritedRecipe, 0, len(tallies))
+	for id, t := range tallies {
+		if i := findRecipe(id); i >= 0 && t.count > 0 {
+			out = append(out, FavoritedRecipe{Recipe: fresh(recipes[i]), Favorites: t.count})
+		}
+	}
+	recipesMu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Favorites != out[j].Favorites {
+			return out[i].Favorites > out[j].Favorites
+		}
+		return tallies[out[i].ID].last.After(tallies[out[j].ID].last)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestMostFavoritedLeaderboard(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.APIKeys = map[string]string{"a": "alice", "b": "bob", "c": "carol"}
+	}, testRecipe("soup", "Soup"), testRecipe("cake", "Cake"), testRecipe("stew", "Stew"), testRecipe("salad", "Salad"))
+
+	for _, fav := range []struct{ key, id string }{
+		{"a", "soup"}, {"b", "soup"}, {"c", "soup"},
+		{"a", "stew"}, {"b", "cake"}, {"a", "cake"},
+		{"c", "stew"},
+		{"c", "stew"}, // repeated favorites count once
+	} {
+		expectStatus(t, serve(router, http.MethodPost, "/recipe/"+fav.id+"/favorite", "", "X-API-KEY", fav.key), http.StatusOK)
+	}
+
+	leaderboard := func(target string) ([]string, []int) {
+		w := serve(router, http.MethodGet, target, "")
+		expectStatus(t, w, http.StatusOK)
+		var ids []string
+		var counts []int
+		for _, r := range decodeBody[[]FavoritedRecipe](t, w) {
+			ids = append(ids, r.ID)
+			counts = append(counts, r.Favorites)
+		}
+		return ids, counts
+	}
+
+	// stew and cake tie on two; stew was favorited more recently.
+	ids, counts := leaderboard("/recipes/most-favorited")
+	if !slices.Equal(ids, []string{"soup", "stew", "cake"}) || !slices.Equal(counts, []int{3, 2, 2}) {
+		t.Errorf("leaderboard = %q with %v, want [soup stew cake] with [3 2 2]", ids, counts)
+	}
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/soup/favorite", "", "X-API-KEY", "a"), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/soup/favorite", "", "X-API-KEY", "b"), http.StatusOK)
+	ids, counts = leaderboard("/recipes/most-favorited?limit=2")
+	if !slices.Equal(ids, []string{"stew", "cake"}) || !slices.Equal(counts, []int{2, 2}) {
+		t.Errorf("leaderboard after unfavoriting = %q with %v, want [stew cake] with [2 2]", ids, counts)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+// knownFeatures are the optional route groups FEATURES can enable. The core
+// recipe routes (list, get, create, update, patch and delete) are always
+// registered.
+var knownFeatures = map[string]bool{
+	"admin":       true, // /admin/*
+	"batch":       true, // /recipes/batch, /recipes/batch-get
+	"clone":       true, // /recipe/:id/clone, /recipe/:id/fork, /recipe/:id/forks
+	"docs":        true, // /swagger/*
+	"export":      true, // /recipes/export.csv, /recipes/export.json, /recipes/flat, /recipes/feed.atom, /recipe/:id/export.pdf, /recipe/:id/qr
+	"favorites":   true, // /recipe/:id/favorite, /recipes/favorites, /recipes/most-favorited
+	"history":     true, // /recipes/recent
+	"images":      true, // /recipe/:id/image
+	"import":      true, // /recipes/import.csv, PUT /recipes
+	"ingredients": true, // /ingredients
+	"kitchen":     true, // scale, timers, difficulty, quantity check, ingredient order, nutrition, cook batches, shopping list
+	"menu":        true, // /menu/suggest, /meal-plan/generate
+	"metrics":     true, // /metrics
+	"pins":        true, // /recipe/:id/pin, /recipe/:id/unpin
+	"schema":      true, // /recipes/schema
+	"search":      true, // /recipes/search, /recipes/search/text, /recipes/multi-search, /recipes/facets, /recipe/:id/similar
+	"sync":        true, // /recipes/ids, /recipes/changes, /recipes/poll
+	"trending":    true, // /recipes/trending
+}
+
+// featureSet is the set of enabled features. nil enables every feature,
+// which is the default when FEATURES is unset; FEATURES=none leaves it empty.
+type featureSet map[string]bool
+
+// on reports whether the routes of feature should be registered.
+func (f featureSet) on(feature string) bool {
+	return f == nil || f[feature]
+}
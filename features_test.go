@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFeatureSubset(t *testing.T) {
+	cfg, err := loadConfig(envMap(map[string]string{"FEATURES": "search, export"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t, func(c *Config) { c.Features = cfg.Features }, testRecipe("r1", "Soup", "vegan"))
+
+	for _, target := range []string{
+		"/recipes",
+		"/recipe/r1",
+		"/recipes/search?tag=vegan",
+		"/recipes/search/text?q=soup",
+		"/recipes/facets",
+		"/recipes/export.json",
+		"/recipes/export.csv",
+	} {
+		expectStatus(t, serve(router, http.MethodGet, target, ""), http.StatusOK)
+	}
+	for _, tc := range []struct{ method, target string }{
+		{http.MethodGet, "/recipe/r1/scale?servings=2"},
+		{http.MethodGet, "/recipes/trending"},
+		{http.MethodGet, "/recipes/schema"},
+		{http.MethodGet, "/metrics"},
+		{http.MethodGet, "/recipes/changes"},
+		{http.MethodPost, "/recipe/r1/clone"},
+		{http.MethodPost, "/admin/warmup"},
+		{http.MethodPost, "/recipes/batch-get"},
+	} {
+		w := serve(router, tc.method, tc.target, "")
+		expectStatus(t, w, http.StatusNotFound)
+		if got := decodeBody[ErrorResponse](t, w).Error; got != "route not found" {
+			t.Errorf("%s %s: error = %q, want route not found", tc.method, tc.target, got)
+		}
+	}
+}
+
+func TestFeaturesNone(t *testing.T) {
+	cfg, err := loadConfig(envMap(map[string]string{"FEATURES": "none"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t, func(c *Config) { c.Features = cfg.Features }, testRecipe("r1", "Soup"))
+
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/r1", ""), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", newRecipeBody), http.StatusCreated)
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/search?tag=vegan", ""), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodGet, "/swagger/index.html", ""), http.StatusNotFound)
+}
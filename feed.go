@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/xml"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// atomFeed and the types below are the parts of an RFC 4287 Atom feed the
+// recipe feed uses.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+// publicBaseURL is the base of absolute links to the API: PUBLIC_BASE_URL
+// when set, otherwise the scheme and host the client used to reach it,
+// honouring X-Forwarded-Proto from a TLS-terminating proxy.
+func publicBaseURL(c *gin.Context) string {
+	if config.PublicBaseURL != "" {
+		return config.PublicBaseURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// instructionsHTML renders r's instructions as an HTML ordered list.
+func instructionsHTML(r Recipe) string {
+	var b strings.Builder
+	b.WriteString("<ol>")
+	for _, step := range r.Instructions {
+		b.WriteString("<li>" + html.EscapeString(step) + "</li>")
+	}
+	b.WriteString("</ol>")
+	return b.String()
+}
+
+// feedPageURL is the feed URL for page, keeping the request's other query
+// parameters.
+func feedPageURL(c *gin.Context, base string, page int) string {
+	q := url.Values{}
+	for k, v := range c.Request.URL.Query() {
+		q[k] = v
+	}
+	q.Set("page", strconv.Itoa(page))
+	return base + c.Request.URL.Path + "?" + q.Encode()
+}
+
+// atomFeedFor builds the feed document for one page of recipes, most
+// recently published first. Links to the next and previous pages follow
+// RFC 5005 paging.
+func atomFeedFor(c *gin.Context, p Page[Recipe]) atomFeed {
+	base := publicBaseURL(c)
+	feed := atomFeed{
+		Title:  "Recipes",
+		ID:     base + "/recipes/feed.atom",
+		Author: atomAuthor{Name: "recipes-api"},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: base + c.Request.URL.RequestURI()},
+			{Rel: "alternate", Type: "application/json", Href: base + "/recipes"},
+		},
+		Entries: make([]atomEntry, 0, len(p.Data)),
+	}
+	if p.Pagination.HasNext {
+		feed.Links = append(feed.Links, atomLink{Rel: "next", Type: "application/atom+xml", Href: feedPageURL(c, base, p.Pagination.Page+1)})
+	}
+	if p.Pagination.HasPrev {
+		feed.Links = append(feed.Links, atomLink{Rel: "previous", Type: "application/atom+xml", Href: feedPageURL(c, base, p.Pagination.Page-1)})
+	}
+
+	var updated time.Time
+	for _, r := range p.Data {
+		modified := lastModified(r)
+		if modified.After(updated) {
+			updated = modified
+		}
+		e := atomEntry{
+			Title:     r.Name,
+			ID:        base + "/recipe/" + url.PathEscape(r.ID),
+			Published: r.PublishedAt.rfc3339(),
+			Updated:   modified.UTC().Format(time.RFC3339),
+			Link:      atomLink{Rel: "alternate", Type: "application/json", Href: base + "/recipe/" + url.PathEscape(r.ID)},
+			Content:   atomContent{Type: "html", Body: instructionsHTML(r)},
+		}
+		for _, t := range r.Tags {
+			e.Categories = append(e.Categories, atomCategory{Term: t})
+		}
+		feed.Entries = append(feed.Entries, e)
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+	return feed
+}
+
+// RecipeFeedHandler serves the most recently published recipes as an Atom
+// feed for feed readers: each entry is titled with the recipe's name and
+// carries its instructions as HTML. It takes the list filters, such as
+// ?tag=, and pages like the list endpoint.
+//
+// @Summary Atom feed of recent recipes
+// @Tags recipes
+// @Produce application/atom+xml
+// @Param tag query string false "Only recipes with this tag"
+// @Param category query string false "Only recipes in this category"
+// @Param q query string false "Only recipes whose name, tags or ingredients contain this text"
+// @Param page query int false "Page number, from 1"
+// @Param limit query int false "Entries per page (default 20, max 100)"
+// @Success 200 {string} string "Atom feed"
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/feed.atom [get]
+func RecipeFeedHandler(c *gin.Context) {
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	list, err := exportedRecipes(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortRecipes(list, sortSpec{field: "publishedAt", desc: true})
+
+	body, err := xml.MarshalIndent(atomFeedFor(c, paginate(list, page, limit)), "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/xml"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// feedTitles fetches the feed at target and returns it with the titles of
+// its entries in order.
+func feedTitles(t *testing.T, router http.Handler, target string) (atomFeed, []string) {
+	t.Helper()
+	w := serve(router, http.MethodGet, target, "")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want application/atom+xml", ct)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("parsing feed %s: %v", w.Body.String(), err)
+	}
+	titles := make([]string, 0)
+	for _, e := range feed.Entries {
+		titles = append(titles, e.Title)
+	}
+	return feed, titles
+}
+
+func TestRecipeFeed(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := []Recipe{
+		testRecipe("r1", "Tomato Soup", "vegan"),
+		testRecipe("r2", "Beef Stew"),
+		testRecipe("r3", "Bean Chili", "vegan"),
+	}
+	seed[2].Instructions = []string{"Soak the beans.", "Simmer <2 hours>."}
+	for i := range seed {
+		seed[i].PublishedAt = Timestamp{base.AddDate(0, 0, i)}
+	}
+	router := newTestRouter(t, func(cfg *Config) { cfg.PublicBaseURL = "https://recipes.example" }, seed...)
+
+	feed, titles := feedTitles(t, router, "/recipes/feed.atom")
+	if want := []string{"Bean Chili", "Beef Stew", "Tomato Soup"}; !slices.Equal(titles, want) {
+		t.Errorf("titles = %q, want %q", titles, want)
+	}
+	first := feed.Entries[0]
+	if first.ID != "https://recipes.example/recipe/r3" || first.Published != "2024-01-03T00:00:00Z" {
+		t.Errorf("first entry id %q published %q", first.ID, first.Published)
+	}
+	if want := "<ol><li>Soak the beans.</li><li>Simmer &lt;2 hours&gt;.</li></ol>"; first.Content.Type != "html" || first.Content.Body != want {
+		t.Errorf("content = %s %q, want html %q", first.Content.Type, first.Content.Body, want)
+	}
+
+	if _, titles := feedTitles(t, router, "/recipes/feed.atom?tag=vegan"); !slices.Equal(titles, []string{"Bean Chili", "Tomato Soup"}) {
+		t.Errorf("tag=vegan titles = %q", titles)
+	}
+
+	feed, titles = feedTitles(t, router, "/recipes/feed.atom?limit=2")
+	if !slices.Equal(titles, []string{"Bean Chili", "Beef Stew"}) {
+		t.Errorf("limit=2 titles = %q", titles)
+	}
+	var next string
+	for _, l := range feed.Links {
+		if l.Rel == "next" {
+			next = l.Href
+		}
+	}
+	if next != "https://recipes.example/recipes/feed.atom?limit=2&page=2" {
+		t.Errorf("next link = %q", next)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listFilter holds the query-string filters accepted by the list and export
+// endpoints.
+type listFilter struct {
+	tag               string
+	category          string
+	text              string
+	excludeAllergens  []string
+	requiresEquipment []string
+	excludeEquipment  []string
+	hasVideo          *bool
+	// maxCost, when set, keeps recipes priced in currency at or under it;
+	// unpriced recipes pass only with includeUnpriced.
+	maxCost         *int
+	currency        string
+	includeUnpriced bool
+}
+
+func parseListFilter(c *gin.Context) (listFilter, error) {
+	f := listFilter{
+		tag:               strings.TrimSpace(c.Query("tag")),
+		category:          strings.ToLower(strings.TrimSpace(c.Query("category"))),
+		text:              strings.TrimSpace(c.Query("q")),
+		excludeAllergens:  normalizeList(splitList(c.Query("excludeAllergens"))),
+		requiresEquipment: normalizeList(splitList(c.Query("requiresEquipment"))),
+		excludeEquipment:  normalizeList(splitList(c.Query("excludeEquipment"))),
+	}
+	if f.category != "" && !knownCategories[f.category] {
+		return f, fmt.Errorf("unknown category %q (allowed: %s)", f.category, strings.Join(sortedKeys(knownCategories), ", "))
+	}
+	if v := c.Query("hasVideo"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("hasVideo must be true or false")
+		}
+		f.hasVideo = &b
+	}
+	if v := c.Query("maxCost"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("maxCost must be a non-negative integer")
+		}
+		f.maxCost = &n
+		f.currency = strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+		if !isCurrencyCode(f.currency) {
+			return f, fmt.Errorf("maxCost requires a 3-letter currency")
+		}
+		f.includeUnpriced = c.Query("includeUnpriced") == "true"
+	}
+	return f, nil
+}
+
+// match reports whether r passes every filter.
+func (f listFilter) match(r Recipe) bool {
+	if f.tag != "" && !recipeHasTag(r, f.tag) {
+		return false
+	}
+	if f.category != "" && r.Category != f.category {
+		return false
+	}
+	if f.text != "" && !recipeMatchesText(r, f.text) {
+		return false
+	}
+	for _, a := range f.excludeAllergens {
+		if containsString(r.Allergens, a) {
+			return false
+		}
+	}
+	for _, e := range f.requiresEquipment {
+		if !containsString(r.Equipment, e) {
+			return false
+		}
+	}
+	for _, e := range f.excludeEquipment {
+		if containsString(r.Equipment, e) {
+			return false
+		}
+	}
+	if f.hasVideo != nil && *f.hasVideo != (len(r.Videos) > 0) {
+		return false
+	}
+	if f.maxCost != nil {
+		if r.Currency == "" {
+			return f.includeUnpriced
+		}
+		if r.Currency != f.currency || r.CostCents > *f.maxCost {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestAllergenValidation(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes",
+		`{"name":"Satay","ingredients":["peanuts"],"instructions":["Grind."],"allergens":["Peanuts","nuts"]}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[Recipe](t, w).Allergens; !slices.Equal(got, []string{"peanuts", "nuts"}) {
+		t.Errorf("allergens = %q, want [peanuts nuts]", got)
+	}
+
+	w = serve(router, http.MethodPost, "/recipes",
+		`{"name":"Toast","ingredients":["bread"],"instructions":["Toast."],"allergens":["gluten","bread"]}`)
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+}
+
+func TestExcludeAllergens(t *testing.T) {
+	nutty := testRecipe("nutty", "Pesto")
+	nutty.Allergens = []string{"nuts", "dairy"}
+	eggy := testRecipe("eggy", "Omelette")
+	eggy.Allergens = []string{"eggs"}
+	router := newTestRouter(t, nil, nutty, eggy, testRecipe("plain", "Rice"))
+
+	got := listIDs(t, router, "/recipes?excludeAllergens=dairy,gluten&sort=name&order=asc")
+	if want := []string{"eggy", "plain"}; !slices.Equal(got, want) {
+		t.Errorf("excludeAllergens=dairy,gluten = %q, want %q", got, want)
+	}
+}
+
+func TestEquipmentRoundTrip(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes",
+		`{"name":"Smoothie","ingredients":["banana"],"instructions":["Blend."],"equipment":[" Blender","blender","Knife "]}`)
+	expectStatus(t, w, http.StatusCreated)
+	id := decodeBody[Recipe](t, w).ID
+
+	w = serve(router, http.MethodGet, "/recipe/"+id, "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[Recipe](t, w).Equipment; !slices.Equal(got, []string{"blender", "knife"}) {
+		t.Errorf("equipment = %q, want [blender knife]", got)
+	}
+}
+
+func TestEquipmentFilters(t *testing.T) {
+	smoothie := testRecipe("smoothie", "Smoothie")
+	smoothie.Equipment = []string{"blender"}
+	soup := testRecipe("soup", "Soup")
+	soup.Equipment = []string{"blender", "oven"}
+	salad := testRecipe("salad", "Salad")
+	router := newTestRouter(t, nil, smoothie, soup, salad)
+
+	if got, want := listIDs(t, router, "/recipes?requiresEquipment=Blender&sort=name&order=asc"), []string{"smoothie", "soup"}; !slices.Equal(got, want) {
+		t.Errorf("requiresEquipment=Blender = %q, want %q", got, want)
+	}
+	if got, want := listIDs(t, router, "/recipes?excludeEquipment=oven&sort=name&order=asc"), []string{"salad", "smoothie"}; !slices.Equal(got, want) {
+		t.Errorf("excludeEquipment=oven = %q, want %q", got, want)
+	}
+	if got, want := listIDs(t, router, "/recipes?requiresEquipment=blender&excludeEquipment=oven"), []string{"smoothie"}; !slices.Equal(got, want) {
+		t.Errorf("both filters = %q, want %q", got, want)
+	}
+}
+
+func TestHasVideoFilter(t *testing.T) {
+	withVideo := testRecipe("video", "Bread")
+	withVideo.Videos = []Video{{Title: "Kneading", URL: "https://example.com/knead"}}
+	router := newTestRouter(t, nil, withVideo, testRecipe("plain", "Rice"))
+
+	if got := listIDs(t, router, "/recipes?hasVideo=true"); !slices.Equal(got, []string{"video"}) {
+		t.Errorf("hasVideo=true = %q, want [video]", got)
+	}
+	if got := listIDs(t, router, "/recipes?hasVideo=false"); !slices.Equal(got, []string{"plain"}) {
+		t.Errorf("hasVideo=false = %q, want [plain]", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes?hasVideo=maybe", ""), http.StatusBadRequest)
+}
+
+func TestCostValidation(t *testing.T) {
+	router := newTestRouter(t, nil)
+	post := func(cost string) int {
+		return serve(router, http.MethodPost, "/recipes",
+			`{"name":"Bread","ingredients":["flour"],"instructions":["Bake."],`+cost+`}`).Code
+	}
+
+	w := serve(router, http.MethodPost, "/recipes",
+		`{"name":"Bread","ingredients":["flour"],"instructions":["Bake."],"costCents":450,"currency":" usd "}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[Recipe](t, w); got.CostCents != 450 || got.Currency != "USD" {
+		t.Errorf("cost = %d %q, want 450 USD", got.CostCents, got.Currency)
+	}
+	for _, cost := range []string{
+		`"costCents":-1,"currency":"USD"`,
+		`"costCents":100,"currency":"dollars"`,
+		`"costCents":100`,
+	} {
+		if code := post(cost); code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status = %d, want 422", cost, code)
+		}
+	}
+}
+
+func TestMaxCostFilter(t *testing.T) {
+	priced := func(id string, cents int, currency string) Recipe {
+		r := testRecipe(id, id)
+		r.CostCents, r.Currency = cents, currency
+		return r
+	}
+	router := newTestRouter(t, nil,
+		priced("cheap", 300, "USD"),
+		priced("pricey", 2000, "USD"),
+		priced("euro", 100, "EUR"),
+		testRecipe("unpriced", "unpriced"),
+	)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"maxCost=500&currency=USD", []string{"cheap"}},
+		{"maxCost=2000&currency=usd", []string{"cheap", "pricey"}},
+		{"maxCost=500&currency=USD&includeUnpriced=true", []string{"cheap", "unpriced"}},
+		{"maxCost=500&currency=EUR", []string{"euro"}},
+	} {
+		got := listIDs(t, router, "/recipes?"+tc.query)
+		slices.Sort(got)
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.query, got, tc.want)
+		}
+	}
+	for _, q := range []string{"maxCost=-5&currency=USD", "maxCost=cheap&currency=USD", "maxCost=500"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipes?"+q, ""), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlatIngredientRow is one ingredient of one recipe, denormalized with the
+// recipe's ID, name and tags for loading into a data warehouse. Position is
+// the 1-based index of the ingredient in its recipe and Tags joins the
+// recipe's tags with csvListSeparator.
+type FlatIngredientRow struct {
+	RecipeID   string `json:"recipeId"`
+	RecipeName string `json:"recipeName"`
+	Position   int    `json:"position"`
+	Ingredient string `json:"ingredient"`
+	Tags       string `json:"tags,omitempty"`
+}
+
+// flatColumns is the header of the CSV form of the flat table.
+var flatColumns = []string{"recipeId", "recipeName", "position", "ingredient", "tags"}
+
+// flatRows returns one row per ingredient of each recipe in list, in order.
+func flatRows(list []Recipe) []FlatIngredientRow {
+	rows := make([]FlatIngredientRow, 0)
+	for _, r := range list {
+		tags := strings.Join(r.Tags, csvListSeparator)
+		for i, ingredient := range r.Ingredients {
+			rows = append(rows, FlatIngredientRow{
+				RecipeID:   r.ID,
+				RecipeName: r.Name,
+				Position:   i + 1,
+				Ingredient: ingredient,
+				Tags:       tags,
+			})
+		}
+	}
+	return rows
+}
+
+// FlatRecipesHandler returns the recipes matching the list filters as a flat
+// table with one row per ingredient, as JSON or, with ?format=csv, as CSV
+// with a header row. Recipes without ingredients have no rows.
+//
+// @Summary Export recipes as one row per ingredient
+// @Tags recipes
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "json (default) or csv"
+// @Param tag query string false "Only recipes with this tag"
+// @Param category query string false "Only recipes in this category"
+// @Param q query string false "Only recipes whose name, tags or ingredients contain this text"
+// @Success 200 {array} FlatIngredientRow
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/flat [get]
+func FlatRecipesHandler(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+	list, err := exportedRecipes(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rows := flatRows(list)
+	if format == "json" {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, flatColumns)
+	for _, row := range rows {
+		records = append(records, []string{row.RecipeID, row.RecipeName, strconv.Itoa(row.Position), row.Ingredient, row.Tags})
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="recipes-flat.csv"`)
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	w.WriteAll(records)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func flatSeed() []Recipe {
+	a := testRecipe("a", "Pancakes", "breakfast", "sweet")
+	a.Ingredients = []string{"2 eggs", "1 cup milk", "1 cup flour"}
+	b := testRecipe("b", "Toast", "breakfast")
+	b.Ingredients = []string{"bread", "butter"}
+	empty := testRecipe("c", "Water", "drink")
+	empty.Ingredients = nil
+	return []Recipe{a, b, empty}
+}
+
+func TestFlatRowsPerIngredient(t *testing.T) {
+	router := newTestRouter(t, nil, flatSeed()...)
+
+	w := serve(router, http.MethodGet, "/recipes/flat", "")
+	expectStatus(t, w, http.StatusOK)
+	rows := decodeBody[[]FlatIngredientRow](t, w)
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want one per ingredient: 5", len(rows))
+	}
+	want := FlatIngredientRow{RecipeID: "a", RecipeName: "Pancakes", Position: 2, Ingredient: "1 cup milk", Tags: "breakfast|sweet"}
+	if rows[1] != want {
+		t.Errorf("row 1 = %+v, want %+v", rows[1], want)
+	}
+	if last := rows[4]; last.RecipeID != "b" || last.Position != 2 || last.Ingredient != "butter" {
+		t.Errorf("last row = %+v, want Toast's butter", last)
+	}
+
+	w = serve(router, http.MethodGet, "/recipes/flat?tag=sweet", "")
+	expectStatus(t, w, http.StatusOK)
+	if rows := decodeBody[[]FlatIngredientRow](t, w); len(rows) != 3 {
+		t.Errorf("filtered to sweet: %d rows, want 3", len(rows))
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/flat?format=xml", ""), http.StatusBadRequest)
+}
+
+func TestFlatCSV(t *testing.T) {
+	router := newTestRouter(t, nil, flatSeed()...)
+
+	w := serve(router, http.MethodGet, "/recipes/flat?format=csv", "")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || !slices.Equal(records[0], flatColumns) {
+		t.Fatalf("got %d records starting %q, want a header and 5 rows", len(records), records[0])
+	}
+	if want := []string{"a", "Pancakes", "1", "2 eggs", "breakfast|sweet"}; !slices.Equal(records[1], want) {
+		t.Errorf("first row = %q, want %q", records[1], want)
+	}
+}
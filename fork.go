@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// orphanForks clears the ParentID of every fork of id, touching UpdatedAt
+// so the change reaches sync clients. Callers must hold recipesMu for
+// writing and call recipesChanged.
+func orphanForks(id string, now time.Time) {
+	for i := range recipes {
+		if recipes[i].ParentID == id {
+			recipes[i].ParentID = ""
+			recipes[i].UpdatedAt = Timestamp{now}
+		}
+	}
+}
+
+// ForkRecipeHandler saves a copy of a recipe under a new ID with its
+// ParentID set to the original, so variants keep track of where they came
+// from. Unlike a clone the fork keeps the original's name.
+//
+// @Summary Fork a recipe
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 201 {object} Recipe
+// @Failure 404 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse
+// @Router /recipe/{id}/fork [post]
+func ForkRecipeHandler(c *gin.Context) {
+	recipesMu.Lock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	fork, _ := cloneRecipe(recipes[i], 0)
+	fork.Name = recipes[i].Name
+	fork.ParentID = recipes[i].ID
+	evicted, ok := config.Capacity.makeRoom()
+	if !ok {
+		recipesMu.Unlock()
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)})
+		return
+	}
+	if evicted == fork.ParentID {
+		// Making room evicted the original itself, so the fork starts out
+		// orphaned like any other fork of a deleted recipe.
+		fork.ParentID = ""
+	}
+	fork.ID = newRecipeID()
+	fork.PublishedAt = Timestamp{time.Now()}
+	fork.UpdatedAt = fork.PublishedAt
+	insertRecipe(fork)
+	recipesMu.Unlock()
+
+	if evicted != "" {
+		auditor.record(c, "evict", evicted)
+	}
+	auditor.record(c, "create", fork.ID)
+	respondRecipe(c, http.StatusCreated, fork)
+}
+
+// ListForksHandler lists the direct forks of a recipe in store order.
+//
+// @Summary List a recipe's forks
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {array} Recipe
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/forks [get]
+func ListForksHandler(c *gin.Context) {
+	id := c.Param("id")
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	if findRecipe(id) < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	out := []Recipe{}
+	for _, r := range recipes {
+		if r.ParentID == id {
+			out = append(out, fresh(r))
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// forkOf forks id and returns the new recipe.
+func forkOf(t *testing.T, router http.Handler, id string) Recipe {
+	t.Helper()
+	w := serve(router, http.MethodPost, "/recipe/"+id+"/fork", "")
+	expectStatus(t, w, http.StatusCreated)
+	return decodeBody[Recipe](t, w)
+}
+
+// forkIDs lists the IDs of id's forks.
+func forkIDs(t *testing.T, router http.Handler, id string) []string {
+	t.Helper()
+	w := serve(router, http.MethodGet, "/recipe/"+id+"/forks", "")
+	expectStatus(t, w, http.StatusOK)
+	ids := make([]string, 0)
+	for _, r := range decodeBody[[]Recipe](t, w) {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestForkAndListForks(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("base", "Bread", "baking"), testRecipe("other", "Soup"))
+
+	a := forkOf(t, router, "base")
+	if a.ParentID != "base" || a.Name != "Bread" || a.ID == "base" || !slices.Equal(a.Tags, []string{"baking"}) {
+		t.Errorf("fork = %+v, want a copy of base with parentId base", a)
+	}
+	b := forkOf(t, router, "base")
+	grandchild := forkOf(t, router, a.ID)
+	if grandchild.ParentID != a.ID {
+		t.Errorf("fork of a fork has parentId %q, want %q", grandchild.ParentID, a.ID)
+	}
+
+	if got, want := forkIDs(t, router, "base"), []string{a.ID, b.ID}; !slices.Equal(got, want) {
+		t.Errorf("forks of base = %q, want %q", got, want)
+	}
+	if got := forkIDs(t, router, "other"); len(got) != 0 {
+		t.Errorf("forks of other = %q, want none", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/forks", ""), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/missing/fork", ""), http.StatusNotFound)
+
+	// Clients cannot set the parent themselves.
+	w := serve(router, http.MethodPost, "/recipes", `{"name":"Toast","parentId":"base","ingredients":["bread"],"instructions":["Toast."]}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[Recipe](t, w).ParentID; got != "" {
+		t.Errorf("created recipe has parentId %q", got)
+	}
+}
+
+func TestDeletingParentOrphansForks(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("base", "Bread"))
+	a := forkOf(t, router, "base")
+	grandchild := forkOf(t, router, a.ID)
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/base", ""), http.StatusOK)
+	orphan := storedRecipe(t, a.ID)
+	if orphan.ParentID != "" {
+		t.Errorf("fork still has parentId %q", orphan.ParentID)
+	}
+	if orphan.UpdatedAt.Before(a.UpdatedAt.Time) {
+		t.Errorf("orphan updatedAt %v went backwards from %v", orphan.UpdatedAt, a.UpdatedAt)
+	}
+	if got := storedRecipe(t, grandchild.ID).ParentID; got != a.ID {
+		t.Errorf("grandchild parentId = %q, want it kept as %s", got, a.ID)
+	}
+}
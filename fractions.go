@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// fractionUnits are the canonical units, keyed as in kitchenUnits, whose
+// amounts cooks write as fractions. The empty key is for bare counts such as
+// "2 eggs". Metric units keep decimals.
+var fractionUnits = map[string]bool{
+	"": true, "cup": true, "tbsp": true, "tsp": true,
+	"oz": true, "lb": true, "pinch": true, "clove": true,
+}
+
+// kitchenFractions are the fractions quantities are rounded to, in
+// increasing order: eighths, quarters, thirds and halves.
+var kitchenFractions = []struct {
+	value float64
+	text  string
+}{
+	{0, ""},
+	{1.0 / 8, "1/8"},
+	{1.0 / 4, "1/4"},
+	{1.0 / 3, "1/3"},
+	{3.0 / 8, "3/8"},
+	{1.0 / 2, "1/2"},
+	{5.0 / 8, "5/8"},
+	{2.0 / 3, "2/3"},
+	{3.0 / 4, "3/4"},
+	{7.0 / 8, "7/8"},
+	{1, ""},
+}
+
+// formatKitchenQuantity renders qty of unit the way a recipe would print
+// it, returning the text and the value it stands for. Amounts in
+// fractionUnits are rounded to the nearest of kitchenFractions, so 0.3333
+// cups is "1/3" and 1.49 is "1 1/2"; anything else, and amounts too small
+// to round to an eighth, are formatted by formatQuantity.
+func formatKitchenQuantity(qty float64, unit string) (string, float64) {
+	if !fractionUnits[unit] || qty <= 0 {
+		return formatQuantity(qty), qty
+	}
+	whole := math.Floor(qty)
+	nearest := kitchenFractions[0]
+	for _, f := range kitchenFractions[1:] {
+		if math.Abs(qty-whole-f.value) < math.Abs(qty-whole-nearest.value) {
+			nearest = f
+		}
+	}
+	if nearest.value == 1 {
+		whole++
+	}
+	value := whole
+	if nearest.text != "" {
+		value += nearest.value
+	}
+	switch {
+	case value == 0:
+		return formatQuantity(qty), qty
+	case whole == 0:
+		return nearest.text, value
+	case nearest.text == "":
+		return strconv.FormatFloat(whole, 'f', -1, 64), value
+	}
+	return strconv.FormatFloat(whole, 'f', -1, 64) + " " + nearest.text, value
+}
+
+// unitPlurals maps the singular spellings of kitchenUnits to their plurals.
+var unitPlurals = map[string]string{
+	"cup": "cups", "tablespoon": "tablespoons", "teaspoon": "teaspoons",
+	"gram": "grams", "kilogram": "kilograms",
+	"millilitre": "millilitres", "milliliter": "milliliters",
+	"litre": "litres", "liter": "liters",
+	"ounce": "ounces", "lb": "lbs", "pound": "pounds",
+	"pinch": "pinches", "clove": "cloves",
+}
+
+// inflectUnit makes the unit word that starts text agree with qty: plural
+// above 1 and singular otherwise, so scaling "3 cups" by a third gives
+// "1 cup". Abbreviations such as "tbsp" and text not starting with a known
+// unit are left as they are.
+func inflectUnit(text string, qty float64) string {
+	first, rest, found := strings.Cut(text, " ")
+	word := first
+	if qty > 1 {
+		if plural, ok := unitPlurals[first]; ok {
+			word = plural
+		}
+	} else {
+		for singular, plural := range unitPlurals {
+			if first == plural {
+				word = singular
+				break
+			}
+		}
+	}
+	if !found {
+		return word
+	}
+	return word + " " + rest
+}
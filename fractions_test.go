@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestFormatKitchenQuantity(t *testing.T) {
+	for _, tc := range []struct {
+		qty   float64
+		unit  string
+		want  string
+		value float64
+	}{
+		{1.0 / 3, "cup", "1/3", 1.0 / 3},
+		{0.3333, "cup", "1/3", 1.0 / 3},
+		{0.26, "tsp", "1/4", 0.25},
+		{0.7, "cup", "2/3", 2.0 / 3},
+		{1.49, "", "1 1/2", 1.5},
+		{2.97, "tbsp", "3", 3},
+		{0.01, "cup", "0.01", 0.01},
+		{0.3333, "g", "0.33", 0.3333},
+		{250, "ml", "250", 250},
+	} {
+		text, value := formatKitchenQuantity(tc.qty, tc.unit)
+		if text != tc.want || value != tc.value {
+			t.Errorf("formatKitchenQuantity(%v, %q) = %q, %v; want %q, %v", tc.qty, tc.unit, text, value, tc.want, tc.value)
+		}
+	}
+}
+
+func TestScaleRendersFractions(t *testing.T) {
+	r := testRecipe("r1", "Cake")
+	r.Servings = 3
+	r.Ingredients = []string{"1 cup flour", "3 cups milk", "2 eggs", "100 g sugar"}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/scale?servings=1", "")
+	expectStatus(t, w, http.StatusOK)
+	want := []string{"1/3 cup flour", "1 cup milk", "2/3 eggs", "33.33 g sugar"}
+	if got := decodeBody[ScaledRecipe](t, w).Ingredients; !slices.Equal(got, want) {
+		t.Errorf("ingredients = %q, want %q", got, want)
+	}
+}
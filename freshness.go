@@ -0,0 +1,54 @@
+package main
+
+import "time"
+
+// freshWindow is how long a recipe counts as new after publication, or as
+// updated after its last change.
+const freshWindow = 7 * 24 * time.Hour
+
+// clock returns the current time for values computed at response time.
+// Tests replace it to freeze time.
+var clock = time.Now
+
+// recipeFreshness is "new" for a recipe published within freshWindow of now,
+// "updated" for one changed within it, and "" otherwise.
+func recipeFreshness(r Recipe, now time.Time) string {
+	switch {
+	case now.Sub(r.PublishedAt.Time) < freshWindow:
+		return "new"
+	case now.Sub(r.UpdatedAt.Time) < freshWindow:
+		return "updated"
+	}
+	return ""
+}
+
+// fresh returns r with Freshness set for the current time. Every handler
+// that responds with recipes passes them through fresh or freshList.
+func fresh(r Recipe) Recipe {
+	r.Freshness = recipeFreshness(r, clock())
+	return r
+}
+
+// freshList sets Freshness on each recipe of list in place, so list must be
+// the caller's own copy rather than the store.
+func freshList(list []Recipe) []Recipe {
+	now := clock()
+	for i := range list {
+		list[i].Freshness = recipeFreshness(list[i], now)
+	}
+	return list
+}
+
+// nextFreshnessChange returns the earliest time after now at which the
+// freshness of a recipe in list changes, or the zero time if none will.
+func nextFreshnessChange(list []Recipe, now time.Time) time.Time {
+	var next time.Time
+	for _, r := range list {
+		for _, t := range []time.Time{r.PublishedAt.Add(freshWindow), r.UpdatedAt.Add(freshWindow)} {
+			if t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	return next
+}
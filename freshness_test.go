@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFreshness(t *testing.T) {
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	dated := func(id string, published, updated time.Duration) Recipe {
+		r := testRecipe(id, id)
+		r.PublishedAt = Timestamp{now.Add(-published)}
+		r.UpdatedAt = Timestamp{now.Add(-updated)}
+		return r
+	}
+	router := newTestRouter(t, nil,
+		dated("new", day, day),
+		dated("updated", 30*day, 2*day),
+		dated("stale", 30*day, 20*day),
+	)
+	clock = func() time.Time { return now }
+
+	want := map[string]string{"new": "new", "updated": "updated", "stale": ""}
+	check := func(want map[string]string) {
+		t.Helper()
+		for id, state := range want {
+			w := serve(router, http.MethodGet, "/recipe/"+id, "")
+			expectStatus(t, w, http.StatusOK)
+			if got := decodeBody[Recipe](t, w).Freshness; got != state {
+				t.Errorf("GET %s: freshness = %q, want %q", id, got, state)
+			}
+		}
+		w := serve(router, http.MethodGet, "/recipes", "")
+		expectStatus(t, w, http.StatusOK)
+		for _, r := range decodeBody[PaginatedRecipes](t, w).Data {
+			if r.Freshness != want[r.ID] {
+				t.Errorf("list %s: freshness = %q, want %q", r.ID, r.Freshness, want[r.ID])
+			}
+		}
+	}
+	check(want)
+
+	// Six days on, "new" has aged out and the cached list must follow.
+	now = now.Add(6 * day)
+	check(map[string]string{"new": "", "updated": "", "stale": ""})
+	if freshness := recipeFreshness(storedRecipe(t, "new"), now.Add(-time.Minute)); freshness != "new" {
+		t.Errorf("a minute before the window closes, freshness = %q, want new", freshness)
+	}
+}
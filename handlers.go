@@ -0,0 +1,420 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBatchIDs bounds the number of IDs accepted by a single batch request.
+const maxBatchIDs = 100
+
+// BatchGetRequest is the body of POST /recipes/batch-get.
+type BatchGetRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// BatchGetResponse holds the recipes found, in request order, and the IDs
+// that did not match any recipe.
+type BatchGetResponse struct {
+	Recipes  []Recipe `json:"recipes"`
+	NotFound []string `json:"notFound"`
+}
+
+// ErrorResponse documents the JSON body returned with every error status.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NotFoundHandler answers requests that match no route.
+func NotFoundHandler(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{"error": "route not found"})
+}
+
+// RecoveryHandler answers a request whose handler panicked. The panic is
+// logged by gin's recovery middleware; the client only learns that the
+// request failed.
+func RecoveryHandler(c *gin.Context, _ any) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
+
+// NewRecipeHandler creates a recipe from the JSON or form-encoded body. The
+// configured default tags are added unless ?noDefaultTags=true, as are any
+// tags derived from the ingredients (see autoTagger).
+//
+// @Summary Create a recipe
+// @Tags recipes
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param recipe body Recipe true "Recipe to create"
+// @Param lint query bool false "Return warnings for instructions mentioning unlisted ingredients"
+// @Param noDefaultTags query bool false "Skip the configured default tags"
+// @Success 201 {object} Recipe
+// @Failure 400 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse
+// @Router /recipes [post]
+func NewRecipeHandler(c *gin.Context) {
+	var recipe Recipe
+	if err := bindRecipe(c, &recipe); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("noDefaultTags") != "true" {
+		recipe.Tags = append(recipe.Tags, config.DefaultTags...)
+	}
+	recipe.Tags = append(recipe.Tags, autoTags.derive(recipe)...)
+	normalizeRecipe(&recipe)
+	if err := validateRecipe(&recipe); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	recipe.Thumbnail = ""
+	recipe.Pinned = false
+	recipe.ParentID = ""
+	recipe.ID = newRecipeID()
+	recipe.PublishedAt = Timestamp{time.Now()}
+	recipe.UpdatedAt = recipe.PublishedAt
+
+	recipesMu.Lock()
+	evicted, ok := config.Capacity.makeRoom()
+	if !ok {
+		recipesMu.Unlock()
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)})
+		return
+	}
+	insertRecipe(recipe)
+	recipesMu.Unlock()
+
+	if evicted != "" {
+		auditor.record(c, "evict", evicted)
+	}
+	auditor.record(c, "create", recipe.ID)
+	respondRecipe(c, http.StatusCreated, recipe)
+}
+
+// ListRecipesHandler returns a page of the recipes matching the query
+// filters, ordered by ?sort= and ?order= or the configured default. Pinned
+// recipes always come first.
+//
+// @Summary List recipes
+// @Tags recipes
+// @Produce json
+// @Param tag query string false "Only recipes with this tag"
+// @Param category query string false "Only recipes in this category"
+// @Param q query string false "Only recipes whose name, tags or ingredients contain this text"
+// @Param excludeAllergens query string false "Comma-separated allergens to exclude"
+// @Param requiresEquipment query string false "Comma-separated equipment every result must need"
+// @Param excludeEquipment query string false "Comma-separated equipment to exclude"
+// @Param hasVideo query bool false "Only recipes with (true) or without (false) videos"
+// @Param maxCost query int false "Maximum costCents, in the given currency"
+// @Param currency query string false "3-letter currency for maxCost"
+// @Param includeUnpriced query bool false "Keep recipes without a cost when filtering by maxCost"
+// @Param sort query string false "name, publishedAt or updatedAt (default publishedAt)"
+// @Param order query string false "asc or desc (default desc)"
+// @Param page query int false "Page number, from 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} PaginatedRecipes
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes [get]
+// @Router /recipes [head]
+func ListRecipesHandler(c *gin.Context) {
+	if len(c.Request.URL.RawQuery) == 0 {
+		body, err := cachedRecipeList()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		writeJSONBody(c, http.StatusOK, body)
+		return
+	}
+
+	filter, err := parseListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	spec, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipesMu.RLock()
+	out := make([]Recipe, 0, len(recipes))
+	for _, r := range recipes {
+		if filter.match(r) {
+			out = append(out, r)
+		}
+	}
+	out = pinnedFirst(freshList(out), spec)
+	body, err := json.Marshal(paginate(out, page, limit))
+	recipesMu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeJSONBody(c, http.StatusOK, body)
+}
+
+// cachedRecipeList returns the marshaled first page of the unfiltered recipe
+// list, rebuilding it if a mutation has invalidated it or the freshness of a
+// recipe on it has changed since.
+func cachedRecipeList() ([]byte, error) {
+	recipesMu.RLock()
+	body := listCache
+	valid := listCacheValid(clock())
+	recipesMu.RUnlock()
+	if valid {
+		return body, nil
+	}
+
+	recipesMu.Lock()
+	defer recipesMu.Unlock()
+	now := clock()
+	if !listCacheValid(now) {
+		sorted := append([]Recipe(nil), recipes...)
+		sorted = pinnedFirst(freshList(sorted), config.DefaultSort)
+		page := paginate(sorted, 1, defaultPageLimit)
+		encoded, err := json.Marshal(page)
+		if err != nil {
+			return nil, err
+		}
+		listCache = encoded
+		listCacheExpires = nextFreshnessChange(page.Data, now)
+	}
+	return listCache, nil
+}
+
+// listCacheValid reports whether listCache can be served at now. Callers
+// must hold recipesMu.
+func listCacheValid(now time.Time) bool {
+	return listCache != nil && (listCacheExpires.IsZero() || now.Before(listCacheExpires))
+}
+
+// GetRecipeHandler returns a single recipe, in the translation that best
+// matches Accept-Language (see localizeRecipe). Every GET counts towards
+// trending, and GETs by authenticated users are recorded in their recently
+// viewed history. HEAD returns the same headers without counting a view.
+//
+// @Summary Get a recipe
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param Accept-Language header string false "Preferred languages, e.g. es;q=0.9, en;q=0.8"
+// @Success 200 {object} Recipe
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id} [get]
+// @Router /recipe/{id} [head]
+func GetRecipeHandler(c *gin.Context) {
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		if c.Request.Method == http.MethodHead {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := fresh(recipes[i])
+	recipesMu.RUnlock()
+	recipe = localizeRecipe(recipe, c.GetHeader("Accept-Language"))
+
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	if recipe.Language != "" {
+		c.Header("Content-Language", recipe.Language)
+	}
+	body, err := json.Marshal(recipe)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Request.Method == http.MethodGet {
+		recipeViews.record(recipe.ID, time.Now())
+		if user, ok := currentUser(c); ok {
+			recentlyViewed.record(user, recipe.ID)
+		}
+	}
+	writeJSONBody(c, http.StatusOK, body)
+}
+
+// UpdateRecipeHandler replaces a recipe with the JSON or form-encoded body,
+// keeping its ID and publication time. If no recipe has the ID it is created
+// under that ID instead, so sync clients can upsert; the ID must then be
+// valid in the configured ID scheme, and the status (201 or 200) tells the
+// two cases apart.
+//
+// @Summary Create or replace a recipe
+// @Tags recipes
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param recipe body Recipe true "Replacement recipe"
+// @Param lint query bool false "Return warnings for instructions mentioning unlisted ingredients"
+// @Param noDefaultTags query bool false "Skip the configured default tags when creating"
+// @Success 200 {object} Recipe "Updated"
+// @Success 201 {object} Recipe "Created"
+// @Failure 400 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse
+// @Router /recipe/{id} [put]
+func UpdateRecipeHandler(c *gin.Context) {
+	var recipe Recipe
+	if err := bindRecipe(c, &recipe); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	normalizeRecipe(&recipe)
+	if err := validateRecipe(&recipe); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	now := time.Now()
+	recipesMu.Lock()
+	i := findRecipe(id)
+	if i < 0 {
+		if !config.IDScheme.valid(id) {
+			recipesMu.Unlock()
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid recipe id %q for the %s id scheme", id, config.IDScheme.name)})
+			return
+		}
+		evicted, ok := config.Capacity.makeRoom()
+		if !ok {
+			recipesMu.Unlock()
+			c.JSON(http.StatusInsufficientStorage, gin.H{"error": fmt.Sprintf("recipe limit of %d reached", config.Capacity.max)})
+			return
+		}
+		if c.Query("noDefaultTags") != "true" {
+			recipe.Tags = append(recipe.Tags, config.DefaultTags...)
+		}
+		recipe.Tags = normalizeTags(append(recipe.Tags, autoTags.derive(recipe)...))
+		recipe.ID = id
+		recipe.Thumbnail = ""
+		recipe.Pinned = false
+		recipe.ParentID = ""
+		recipe.PublishedAt = Timestamp{now}
+		recipe.UpdatedAt = Timestamp{now}
+		insertRecipe(recipe)
+		recipesMu.Unlock()
+
+		if evicted != "" {
+			auditor.record(c, "evict", evicted)
+		}
+		auditor.record(c, "create", recipe.ID)
+		respondRecipe(c, http.StatusCreated, recipe)
+		return
+	}
+	recipe.ID = recipes[i].ID
+	recipe.PublishedAt = recipes[i].PublishedAt
+	recipe.Thumbnail = recipes[i].Thumbnail
+	recipe.Pinned = recipes[i].Pinned
+	recipe.ParentID = recipes[i].ParentID
+	recipe.UpdatedAt = Timestamp{now}
+	replaceRecipe(i, recipe)
+	recipesMu.Unlock()
+
+	auditor.record(c, "update", recipe.ID)
+	respondRecipe(c, http.StatusOK, recipe)
+}
+
+// respondRecipe writes a saved recipe, adding lint warnings when the client
+// asked for them with ?lint=true. Linting never blocks the save.
+func respondRecipe(c *gin.Context, status int, recipe Recipe) {
+	recipe = fresh(recipe)
+	if c.Query("lint") == "true" {
+		c.JSON(status, LintedRecipe{Recipe: recipe, Warnings: lintRecipe(recipe)})
+		return
+	}
+	c.JSON(status, recipe)
+}
+
+// DeleteRecipeHandler removes a recipe. Under config.ConfirmDeletes it
+// takes two steps: a request without ?confirm= only returns a confirmToken,
+// valid for a minute, which a second request must send to delete.
+//
+// @Summary Delete a recipe
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param confirm query string false "Confirmation token, when CONFIRM_DELETES is on"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id} [delete]
+func DeleteRecipeHandler(c *gin.Context) {
+	id := c.Param("id")
+	now := time.Now()
+	token := c.Query("confirm")
+	recipesMu.Lock()
+	i := findRecipe(id)
+	if i < 0 {
+		recipesMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	if config.ConfirmDeletes && token == "" {
+		recipesMu.Unlock()
+		token, expires := pendingDeletes.issue(id, now)
+		c.JSON(http.StatusOK, gin.H{"confirmToken": token, "expiresAt": expires})
+		return
+	}
+	if config.ConfirmDeletes && !pendingDeletes.redeem(token, id, now) {
+		recipesMu.Unlock()
+		c.JSON(http.StatusBadRequest, gin.H{"error": "confirm token is invalid or expired; send DELETE without confirm for a new one"})
+		return
+	}
+	removeRecipe(i)
+	recipesMu.Unlock()
+
+	auditor.record(c, "delete", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Recipe has been deleted"})
+}
+
+// BatchGetRecipesHandler fetches several recipes by ID in one request.
+//
+// @Summary Get several recipes by ID
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param request body BatchGetRequest true "IDs to fetch"
+// @Success 200 {object} BatchGetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /recipes/batch-get [post]
+func BatchGetRecipesHandler(c *gin.Context) {
+	var req BatchGetRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.IDs) > maxBatchIDs {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("at most %d ids per request", maxBatchIDs)})
+		return
+	}
+
+	resp := BatchGetResponse{Recipes: make([]Recipe, 0, len(req.IDs)), NotFound: make([]string, 0)}
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	for _, id := range req.IDs {
+		if i := findRecipe(id); i >= 0 {
+			resp.Recipes = append(resp.Recipes, fresh(recipes[i]))
+		} else {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
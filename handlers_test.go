@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBatchGetFoundAndMissing(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("a", "Apple pie"), testRecipe("b", "Banana bread"))
+
+	w := serve(router, http.MethodPost, "/recipes/batch-get", `{"ids":["b","x","a","y"]}`)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[BatchGetResponse](t, w)
+	var ids []string
+	for _, r := range got.Recipes {
+		ids = append(ids, r.ID)
+	}
+	if !slices.Equal(ids, []string{"b", "a"}) {
+		t.Errorf("recipes = %q, want [b a] in request order", ids)
+	}
+	if !slices.Equal(got.NotFound, []string{"x", "y"}) {
+		t.Errorf("notFound = %q, want [x y]", got.NotFound)
+	}
+}
+
+func TestBatchGetCap(t *testing.T) {
+	router := newTestRouter(t, nil)
+	ids := make([]string, maxBatchIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%q", fmt.Sprint(i))
+	}
+
+	body := `{"ids":[` + strings.Join(ids[:maxBatchIDs], ",") + `]}`
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/batch-get", body), http.StatusOK)
+
+	body = `{"ids":[` + strings.Join(ids, ",") + `]}`
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/batch-get", body), http.StatusUnprocessableEntity)
+}
+
+func TestListCacheInvalidatedOnWrite(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	if got := listIDs(t, router, "/recipes"); !slices.Equal(got, []string{"r1"}) {
+		t.Fatalf("list = %q, want [r1]", got)
+	}
+	if listCache == nil {
+		t.Fatal("unfiltered list was not cached")
+	}
+
+	w := serve(router, http.MethodPost, "/recipes", newRecipeBody)
+	expectStatus(t, w, http.StatusCreated)
+	if listCache != nil {
+		t.Error("create did not invalidate the list cache")
+	}
+	id := decodeBody[Recipe](t, w).ID
+	if got := listIDs(t, router, "/recipes"); len(got) != 2 || !slices.Contains(got, id) {
+		t.Errorf("list after create = %q, want r1 and %s", got, id)
+	}
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/r1", ""), http.StatusOK)
+	if got := listIDs(t, router, "/recipes"); !slices.Equal(got, []string{id}) {
+		t.Errorf("list after delete = %q, want [%s]", got, id)
+	}
+}
+
+// BenchmarkListRecipes compares the cached unfiltered list with the same
+// page built per request, which ?page=1 forces.
+func BenchmarkListRecipes(b *testing.B) {
+	seed := make([]Recipe, 200)
+	for i := range seed {
+		seed[i] = testRecipe(fmt.Sprint(i), fmt.Sprintf("Recipe %d", i), "dinner")
+	}
+	router := newTestRouter(b, nil, seed...)
+	for _, target := range []string{"/recipes", "/recipes?page=1"} {
+		b.Run(target, func(b *testing.B) {
+			for range b.N {
+				serve(router, http.MethodGet, target, "")
+			}
+		})
+	}
+}
+
+func TestDefaultTagsOnCreate(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.DefaultTags = []string{"team-kitchen", "quick"}
+	})
+	body := `{"name":"Toast","tags":["Quick","breakfast"],"ingredients":["bread"],"instructions":["Toast."]}`
+
+	w := serve(router, http.MethodPost, "/recipes", body)
+	expectStatus(t, w, http.StatusCreated)
+	if got, want := decodeBody[Recipe](t, w).Tags, []string{"quick", "breakfast", "team-kitchen"}; !slices.Equal(got, want) {
+		t.Errorf("tags = %q, want %q", got, want)
+	}
+
+	w = serve(router, http.MethodPost, "/recipes?noDefaultTags=true", body)
+	expectStatus(t, w, http.StatusCreated)
+	if got, want := decodeBody[Recipe](t, w).Tags, []string{"quick", "breakfast"}; !slices.Equal(got, want) {
+		t.Errorf("tags with noDefaultTags = %q, want %q", got, want)
+	}
+}
+
+func TestUpsertCreatesThenUpdates(t *testing.T) {
+	router := newTestRouter(t, nil)
+	id := xidScheme.generate()
+
+	w := serve(router, http.MethodPut, "/recipe/"+id, newRecipeBody)
+	expectStatus(t, w, http.StatusCreated)
+	created := decodeBody[Recipe](t, w)
+	if created.ID != id || created.Name != "Toast" {
+		t.Fatalf("created %+v, want Toast with ID %s", created, id)
+	}
+
+	body := `{"name":"French toast","ingredients":["bread","egg"],"instructions":["Dip and fry."]}`
+	w = serve(router, http.MethodPut, "/recipe/"+id, body)
+	expectStatus(t, w, http.StatusOK)
+	updated := decodeBody[Recipe](t, w)
+	if updated.ID != id || updated.Name != "French toast" {
+		t.Errorf("updated %+v, want French toast with ID %s", updated, id)
+	}
+	if !updated.PublishedAt.Equal(created.PublishedAt.Time) {
+		t.Errorf("publishedAt changed from %v to %v on update", created.PublishedAt, updated.PublishedAt)
+	}
+	if got := storedRecipe(t, id).Name; got != "French toast" {
+		t.Errorf("stored name = %q, want French toast", got)
+	}
+}
+
+func TestUpsertRejectsInvalidID(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) { cfg.IDScheme = uuidv7Scheme })
+
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/"+xidScheme.generate(), newRecipeBody), http.StatusBadRequest)
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/not-an-id", newRecipeBody), http.StatusBadRequest)
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/"+newUUIDv7(), newRecipeBody), http.StatusCreated)
+}
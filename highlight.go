@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Highlight markers wrapped around matched text. Everything else in a
+// snippet is HTML-escaped, so snippets are safe to insert as markup.
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+// HighlightedRecipe is a text search result with a snippet for each field
+// that matched, keyed like "name", "tags[0]" or "ingredients[2]".
+type HighlightedRecipe struct {
+	Recipe
+	Highlights map[string]string `json:"highlights"`
+}
+
+// highlightText returns s, HTML-escaped, with every occurrence of the folded
+// query wrapped in markers. ok is false when s does not contain query.
+// Matching ignores case and accents like recipeMatchesText, so s is folded
+// rune by rune to map matches back onto the original text.
+func highlightText(s, query string) (snippet string, ok bool) {
+	query = foldText(query)
+	if query == "" {
+		return "", false
+	}
+	var folded strings.Builder
+	// starts[i] is the offset in s of the rune that produced folded byte i.
+	var starts []int
+	for i, r := range s {
+		f := foldText(string(r))
+		folded.WriteString(f)
+		for range len(f) {
+			starts = append(starts, i)
+		}
+	}
+	text := folded.String()
+
+	var b strings.Builder
+	prev := 0
+	for from := 0; from < len(text); {
+		n := strings.Index(text[from:], query)
+		if n < 0 {
+			break
+		}
+		at := from + n
+		end := at + len(query)
+		startOrig := starts[at]
+		endOrig := len(s)
+		if end < len(starts) {
+			endOrig = starts[end]
+		}
+		if startOrig < prev {
+			from = end
+			continue
+		}
+		b.WriteString(html.EscapeString(s[prev:startOrig]))
+		b.WriteString(markOpen + html.EscapeString(s[startOrig:endOrig]) + markClose)
+		prev = endOrig
+		ok = true
+		from = end
+	}
+	if !ok {
+		return "", false
+	}
+	b.WriteString(html.EscapeString(s[prev:]))
+	return b.String(), true
+}
+
+// highlightRecipe collects snippets for the given fields of r that contain
+// query, keyed like "ingredients[2]" for list fields.
+func highlightRecipe(r Recipe, query string, fields []string) HighlightedRecipe {
+	h := HighlightedRecipe{Recipe: r, Highlights: make(map[string]string)}
+	for _, field := range fields {
+		for i, text := range searchFields[field](r) {
+			key := fmt.Sprintf("%s[%d]", field, i)
+			if field == "name" {
+				key = field
+			}
+			if snippet, ok := highlightText(text, query); ok {
+				h.Highlights[key] = snippet
+			}
+		}
+	}
+	return h
+}
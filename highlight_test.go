@@ -0,0 +1,61 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+	"testing"
+)
+
+func TestHighlightText(t *testing.T) {
+	for _, tc := range []struct {
+		s, q, want string
+		ok         bool
+	}{
+		{"Tomato & tofu", "TOFU", "Tomato &amp; <mark>tofu</mark>", true},
+		{"Crème brûlée", "creme", "<mark>Crème</mark> brûlée", true},
+		{"egg, eggs", "egg", "<mark>egg</mark>, <mark>egg</mark>s", true},
+		{"<script>mint</script>", "mint", "&lt;script&gt;<mark>mint</mark>&lt;/script&gt;", true},
+		{"Tomato", "basil", "", false},
+	} {
+		got, ok := highlightText(tc.s, tc.q)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("highlightText(%q, %q) = %q, %v; want %q, %v", tc.s, tc.q, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestTextSearchHighlights(t *testing.T) {
+	r := testRecipe("r1", "Crème <b>brûlée</b> & tart", "dessert")
+	r.Ingredients = []string{"2 eggs", "100 g brulee sugar"}
+	r.Instructions = []string{"Torch the brûlée top."}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipes/search/text?q=brulee&highlight=true", "")
+	expectStatus(t, w, http.StatusOK)
+	results := decodeBody[Page[HighlightedRecipe]](t, w).Data
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	want := map[string]string{
+		"name":           "Crème &lt;b&gt;<mark>brûlée</mark>&lt;/b&gt; &amp; tart",
+		"ingredients[1]": "100 g <mark>brulee</mark> sugar",
+	}
+	if got := results[0].Highlights; !maps.Equal(got, want) {
+		t.Errorf("highlights = %q, want %q", got, want)
+	}
+
+	w = serve(router, http.MethodGet, "/recipes/search/text?q=brulee&field=instructions&highlight=true", "")
+	expectStatus(t, w, http.StatusOK)
+	want = map[string]string{"instructions[0]": "Torch the <mark>brûlée</mark> top."}
+	if got := decodeBody[Page[HighlightedRecipe]](t, w).Data[0].Highlights; !maps.Equal(got, want) {
+		t.Errorf("instruction highlights = %q, want %q", got, want)
+	}
+
+	w = serve(router, http.MethodGet, "/recipes/search/text?q=brulee", "")
+	expectStatus(t, w, http.StatusOK)
+	for _, r := range decodeBody[Page[map[string]any]](t, w).Data {
+		if _, ok := r["highlights"]; ok {
+			t.Error("highlights returned without ?highlight=true")
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recentViews keeps a most-recent-first list of viewed recipe IDs per user,
+// bounded by config.RecentHistory.
+type recentViews struct {
+	mu     sync.Mutex
+	byUser map[string][]string
+}
+
+func newRecentViews() *recentViews {
+	return &recentViews{byUser: make(map[string][]string)}
+}
+
+var recentlyViewed = newRecentViews()
+
+// record moves id to the front of user's history, dropping any earlier view
+// of the same recipe and trimming the list to the configured limit.
+func (r *recentViews) record(user, id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev := r.byUser[user]
+	limit := config.RecentHistory
+	next := make([]string, 0, min(len(prev)+1, limit))
+	next = append(next, id)
+	for _, v := range prev {
+		if v != id && len(next) < limit {
+			next = append(next, v)
+		}
+	}
+	r.byUser[user] = next
+}
+
+// list returns a copy of user's history, most recent first.
+func (r *recentViews) list(user string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.byUser[user]...)
+}
+
+// RecentRecipesHandler returns the caller's recently viewed recipes, most
+// recent first. Recipes deleted since they were viewed are skipped.
+//
+// @Summary List recently viewed recipes
+// @Tags recipes
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} Recipe
+// @Failure 401 {object} ErrorResponse
+// @Router /recipes/recent [get]
+func RecentRecipesHandler(c *gin.Context) {
+	user, _ := currentUser(c)
+	ids := recentlyViewed.list(user)
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	out := make([]Recipe, 0, len(ids))
+	for _, id := range ids {
+		if i := findRecipe(id); i >= 0 {
+			out = append(out, fresh(recipes[i]))
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func recentIDs(t *testing.T, router http.Handler, key string) []string {
+	t.Helper()
+	w := serve(router, http.MethodGet, "/recipes/recent", "", "X-API-KEY", key)
+	expectStatus(t, w, http.StatusOK)
+	ids := make([]string, 0)
+	for _, r := range decodeBody[[]Recipe](t, w) {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestRecentOrderAndDedup(t *testing.T) {
+	router := newTestRouter(t, withUsers, testRecipe("a", "A"), testRecipe("b", "B"), testRecipe("c", "C"))
+
+	for _, id := range []string{"a", "b", "c", "a"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipe/"+id, "", "X-API-KEY", "alice-key"), http.StatusOK)
+	}
+	if got, want := recentIDs(t, router, "alice-key"), []string{"a", "c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("alice's recent = %q, want %q", got, want)
+	}
+	if got := recentIDs(t, router, "bob-key"); len(got) != 0 {
+		t.Errorf("bob's recent = %q, want empty", got)
+	}
+}
+
+func TestRecentHistoryLimit(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.RecentHistory = 2
+	}, testRecipe("a", "A"), testRecipe("b", "B"), testRecipe("c", "C"))
+
+	for _, id := range []string{"a", "b", "c"} {
+		serve(router, http.MethodGet, "/recipe/"+id, "", "X-API-KEY", "alice-key")
+	}
+	if got, want := recentIDs(t, router, "alice-key"), []string{"c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("recent = %q, want %q", got, want)
+	}
+}
+
+func TestRecentRequiresAuth(t *testing.T) {
+	router := newTestRouter(t, withUsers)
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/recent", ""), http.StatusUnauthorized)
+}
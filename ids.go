@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"regexp"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// idScheme generates and recognises recipe IDs.
+type idScheme struct {
+	name     string
+	generate func() string
+	valid    func(id string) bool
+}
+
+var (
+	xidScheme = idScheme{
+		name:     "xid",
+		generate: func() string { return xid.New().String() },
+		valid: func(id string) bool {
+			_, err := xid.FromString(id)
+			return err == nil
+		},
+	}
+	uuidv7Scheme = idScheme{
+		name:     "uuidv7",
+		generate: newUUIDv7,
+		valid:    uuidv7Pattern.MatchString,
+	}
+)
+
+var uuidv7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// newUUIDv7 returns an RFC 9562 version 7 UUID: a 48-bit Unix millisecond
+// timestamp followed by random bits, so IDs sort by creation time.
+func newUUIDv7() string {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		panic(err)
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(u[:6], ms[2:])
+	u[6] = u[6]&0x0f | 0x70
+	u[8] = u[8]&0x3f | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// newRecipeID returns a fresh ID in the configured scheme. Every code path
+// that creates a recipe must use it, except an upsert, which instead checks
+// the client's ID with config.IDScheme.valid.
+func newRecipeID() string {
+	return config.IDScheme.generate()
+}
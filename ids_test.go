@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+var xidPattern = regexp.MustCompile(`^[0-9a-v]{20}$`)
+
+// createdIDs creates recipes by POST, clone and batch create and returns
+// their IDs.
+func createdIDs(t *testing.T, router http.Handler) []string {
+	t.Helper()
+	w := serve(router, http.MethodPost, "/recipes", newRecipeBody)
+	expectStatus(t, w, http.StatusCreated)
+	ids := []string{decodeBody[Recipe](t, w).ID}
+
+	w = serve(router, http.MethodPost, "/recipe/"+ids[0]+"/clone", "")
+	expectStatus(t, w, http.StatusCreated)
+	ids = append(ids, decodeBody[Recipe](t, w).ID)
+
+	w = serve(router, http.MethodPost, "/recipes/batch", "["+newRecipeBody+","+newRecipeBody+"]")
+	expectStatus(t, w, http.StatusCreated)
+	for _, r := range decodeBody[map[string][]BatchCreateResult](t, w)["results"] {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestIDSchemes(t *testing.T) {
+	for _, tc := range []struct {
+		scheme  idScheme
+		pattern *regexp.Regexp
+	}{
+		{xidScheme, xidPattern},
+		{uuidv7Scheme, uuidv7Pattern},
+	} {
+		router := newTestRouter(t, func(cfg *Config) { cfg.IDScheme = tc.scheme })
+		ids := createdIDs(t, router)
+		if len(ids) != 4 {
+			t.Fatalf("%s: created %q, want 4 IDs", tc.scheme.name, ids)
+		}
+		for _, id := range ids {
+			if !tc.pattern.MatchString(id) || !tc.scheme.valid(id) {
+				t.Errorf("%s: ID %q has the wrong format", tc.scheme.name, id)
+			}
+		}
+	}
+}
+
+func TestUUIDv7SortsByTime(t *testing.T) {
+	a := newUUIDv7()
+	for range 5 {
+		b := newUUIDv7()
+		if a[:13] > b[:13] {
+			t.Errorf("%s sorts after the later %s", a, b)
+		}
+		a = b
+	}
+}
+
+func TestLoadConfigIDScheme(t *testing.T) {
+	for value, want := range map[string]string{"": "xid", "xid": "xid", "uuidv7": "uuidv7"} {
+		cfg, err := loadConfig(func(k string) string {
+			if k == "ID_SCHEME" {
+				return value
+			}
+			return ""
+		})
+		if err != nil || cfg.IDScheme.name != want {
+			t.Errorf("ID_SCHEME=%q: scheme %q, err %v; want %s", value, cfg.IDScheme.name, err, want)
+		}
+	}
+	if _, err := loadConfig(func(k string) string {
+		if k == "ID_SCHEME" {
+			return "uuidv4"
+		}
+		return ""
+	}); err == nil {
+		t.Error("ID_SCHEME=uuidv4 was accepted")
+	}
+}
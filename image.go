@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"time"
+
+	// Register the decoders accepted for uploads.
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	thumbnailSize    = 64
+	thumbnailQuality = 40
+	maxImageBytes    = 5 << 20
+	// maxImagePixels bounds the decoded size of an upload. A few kilobytes
+	// of PNG can declare a canvas that takes gigabytes to decode.
+	maxImagePixels = 40_000_000
+)
+
+// thumbnail scales img down to fit within size×size, averaging the source
+// pixels that fall into each destination pixel. Images already small enough
+// are copied unchanged.
+func thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+cr, g+cg, bl+cb, a+ca, n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// thumbnailDataURI encodes a small JPEG thumbnail of img as a data URI.
+func thumbnailDataURI(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumbnail(img, thumbnailSize), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return "", err
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// UploadImageHandler accepts a JPEG, PNG or GIF in the multipart field
+// "image" and stores a thumbnail of it on the recipe. Images of more than
+// maxImagePixels are rejected from their header, before being decoded.
+//
+// @Summary Upload a recipe image
+// @Tags recipes
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param image formData file true "JPEG, PNG or GIF image"
+// @Security ApiKeyAuth
+// @Success 200 {object} Recipe
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /recipe/{id}/image [post]
+func UploadImageHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImageBytes)
+	header, err := c.FormFile("image")
+	if err != nil {
+		status := http.StatusBadRequest
+		if _, tooBig := err.(*http.MaxBytesError); tooBig {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": "image file is required: " + err.Error()})
+		return
+	}
+	f, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported image: " + err.Error()})
+		return
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("image is %d×%d pixels; at most %d megapixels are accepted", cfg.Width, cfg.Height, maxImagePixels/1_000_000)})
+		return
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported image: " + err.Error()})
+		return
+	}
+	thumb, err := thumbnailDataURI(img)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipesMu.Lock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := recipes[i]
+	recipe.Thumbnail = thumb
+	recipe.UpdatedAt = Timestamp{time.Now()}
+	replaceRecipe(i, recipe)
+	recipesMu.Unlock()
+
+	auditor.record(c, "update", recipe.ID)
+	c.JSON(http.StatusOK, fresh(recipe))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// uploadImage posts data as the "image" field of a multipart form.
+func uploadImage(t *testing.T, router http.Handler, id string, data []byte, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("image", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	mw.Close()
+	return serve(router, http.MethodPost, "/recipe/"+id+"/image", body.String(),
+		append([]string{"Content-Type", mw.FormDataContentType()}, headers...)...)
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader returns the signature and IHDR chunk of a w×h PNG: enough for
+// image.DecodeConfig, but no pixel data.
+func pngHeader(w, h uint32) []byte {
+	ihdr := binary.BigEndian.AppendUint32([]byte("IHDR"), w)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, h)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0)
+	out := []byte("\x89PNG\r\n\x1a\n")
+	out = binary.BigEndian.AppendUint32(out, uint32(len(ihdr)-4))
+	out = append(out, ihdr...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestUploadImageThumbnailInList(t *testing.T) {
+	router := newTestRouter(t, withUsers, testRecipe("r1", "Soup"))
+
+	expectStatus(t, uploadImage(t, router, "r1", testPNG(t, 200, 100), "X-API-KEY", "alice-key"), http.StatusOK)
+
+	w := serve(router, http.MethodGet, "/recipes", "")
+	expectStatus(t, w, http.StatusOK)
+	list := decodeBody[PaginatedRecipes](t, w).Data
+	if len(list) != 1 {
+		t.Fatalf("list = %+v, want one recipe", list)
+	}
+	data, ok := strings.CutPrefix(list[0].Thumbnail, "data:image/jpeg;base64,")
+	if !ok {
+		t.Fatalf("thumbnail = %.40q, want a JPEG data URI", list[0].Thumbnail)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumb, err := jpeg.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := thumb.Bounds(); b.Dx() != thumbnailSize || b.Dy() != thumbnailSize/2 {
+		t.Errorf("thumbnail is %dx%d, want %dx%d", b.Dx(), b.Dy(), thumbnailSize, thumbnailSize/2)
+	}
+}
+
+func TestUploadImageRejections(t *testing.T) {
+	router := newTestRouter(t, withUsers, testRecipe("r1", "Soup"))
+	small := testPNG(t, 4, 4)
+
+	expectStatus(t, uploadImage(t, router, "r1", small), http.StatusUnauthorized)
+	expectStatus(t, uploadImage(t, router, "missing", small, "X-API-KEY", "alice-key"), http.StatusNotFound)
+	expectStatus(t, uploadImage(t, router, "r1", []byte("not an image"), "X-API-KEY", "alice-key"), http.StatusBadRequest)
+	expectStatus(t, uploadImage(t, router, "r1", pngHeader(10000, 5000), "X-API-KEY", "alice-key"), http.StatusUnprocessableEntity)
+	if got := storedRecipe(t, "r1").Thumbnail; got != "" {
+		t.Errorf("rejected uploads stored a thumbnail")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idSet is a set of recipe IDs.
+type idSet map[string]struct{}
+
+// recipeIndex is an inverted index over recipe tags and the words of every
+// text search field. It is guarded by recipesMu and kept in sync by
+// the store's mutation helpers.
+type recipeIndex struct {
+	all   idSet
+	tags  map[string]idSet
+	words map[string]idSet
+}
+
+func newRecipeIndex() *recipeIndex {
+	return &recipeIndex{all: make(idSet), tags: make(map[string]idSet), words: make(map[string]idSet)}
+}
+
+var searchIndex = newRecipeIndex()
+
+// textTokens splits folded text into runs of letters and digits.
+func textTokens(s string) []string {
+	return strings.FieldsFunc(foldText(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// indexedText returns the text of every field in searchFields, so the index
+// can narrow a search of any of them.
+func indexedText(r Recipe) []string {
+	fields := make([]string, 0, 1+len(r.Tags)+len(r.Ingredients)+len(r.Instructions))
+	for _, field := range allSearchFields {
+		fields = append(fields, searchFields[field](r)...)
+	}
+	return fields
+}
+
+func addToSet(m map[string]idSet, key, id string) {
+	s := m[key]
+	if s == nil {
+		s = make(idSet)
+		m[key] = s
+	}
+	s[id] = struct{}{}
+}
+
+func removeFromSet(m map[string]idSet, key, id string) {
+	if s := m[key]; s != nil {
+		delete(s, id)
+		if len(s) == 0 {
+			delete(m, key)
+		}
+	}
+}
+
+func (idx *recipeIndex) add(r Recipe) {
+	idx.all[r.ID] = struct{}{}
+	for _, t := range r.Tags {
+		addToSet(idx.tags, foldText(t), r.ID)
+	}
+	for _, field := range indexedText(r) {
+		for _, w := range textTokens(field) {
+			addToSet(idx.words, w, r.ID)
+		}
+	}
+}
+
+func (idx *recipeIndex) remove(r Recipe) {
+	delete(idx.all, r.ID)
+	for _, t := range r.Tags {
+		removeFromSet(idx.tags, foldText(t), r.ID)
+	}
+	for _, field := range indexedText(r) {
+		for _, w := range textTokens(field) {
+			removeFromSet(idx.words, w, r.ID)
+		}
+	}
+}
+
+// rebuild discards the index and builds it again from list.
+func (idx *recipeIndex) rebuild(list []Recipe) {
+	*idx = *newRecipeIndex()
+	for _, r := range list {
+		idx.add(r)
+	}
+}
+
+// withTag returns the IDs of recipes carrying tag.
+func (idx *recipeIndex) withTag(tag string) idSet {
+	return idx.tags[foldText(tag)]
+}
+
+// textCandidates returns a superset of the recipes whose text fields contain
+// query. Any match must contain the query's longest word inside one of its
+// indexed tokens. ok is false when the query has no word to look up and the
+// caller has to scan every recipe.
+func (idx *recipeIndex) textCandidates(query string) (ids idSet, ok bool) {
+	longest := ""
+	for _, w := range textTokens(query) {
+		if len(w) > len(longest) {
+			longest = w
+		}
+	}
+	if longest == "" {
+		return nil, false
+	}
+	ids = make(idSet)
+	for token, set := range idx.words {
+		if !strings.Contains(token, longest) {
+			continue
+		}
+		for id := range set {
+			ids[id] = struct{}{}
+		}
+	}
+	return ids, true
+}
+
+// ReindexHandler rebuilds the search index from the current recipes.
+//
+// @Summary Rebuild the search index
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /admin/reindex [post]
+func ReindexHandler(c *gin.Context) {
+	start := time.Now()
+	recipesMu.Lock()
+	searchIndex.rebuild(recipes)
+	n := len(recipes)
+	recipesMu.Unlock()
+	c.JSON(http.StatusOK, gin.H{"indexed": n, "took": time.Since(start).String()})
+}
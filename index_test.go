@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func sortedIDs(list []Recipe) []string {
+	ids := make([]string, 0, len(list))
+	for _, r := range list {
+		ids = append(ids, r.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// checkIndexMatchesScan compares index-backed tag and text searches with a
+// scan of every recipe.
+func checkIndexMatchesScan(t *testing.T) {
+	t.Helper()
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	for _, q := range []string{"vegan", "Vegan AND quick", "quick OR dessert", "NOT vegan", "vegan NOT (quick OR dessert)", "nosuchtag"} {
+		expr, err := parseTagExpr(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var scanned []Recipe
+		for _, r := range recipes {
+			if expr.eval(r) {
+				scanned = append(scanned, r)
+			}
+		}
+		if got, want := sortedIDs(recipesIn(expr.ids(searchIndex))), sortedIDs(scanned); !slices.Equal(got, want) {
+			t.Errorf("tag expression %q: index found %q, scan %q", q, got, want)
+		}
+	}
+	for _, q := range []string{"tofu", "TOMATO", "ice cream", "crème", "stir", "zz"} {
+		var scanned []Recipe
+		for _, r := range recipes {
+			if recipeMatchesText(r, q, defaultSearchFields...) {
+				scanned = append(scanned, r)
+			}
+		}
+		if got, want := sortedIDs(textSearch(q, defaultSearchFields)), sortedIDs(scanned); !slices.Equal(got, want) {
+			t.Errorf("text %q: index found %q, scan %q", q, got, want)
+		}
+	}
+}
+
+func TestIndexMatchesLinearScan(t *testing.T) {
+	tofu := testRecipe("tofu", "Tofu stir fry", "vegan", "quick")
+	tofu.Ingredients = []string{"200 g tofu", "1 pepper"}
+	sorbet := testRecipe("sorbet", "Mango sorbet", "vegan", "dessert")
+	iceCream := testRecipe("icecream", "Crème ice cream", "dessert")
+	router := newTestRouter(t, withUsers, tofu, sorbet, iceCream, testRecipe("soup", "Tomato soup", "quick"))
+	checkIndexMatchesScan(t)
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipes",
+		`{"name":"Tomato tofu","tags":["vegan"],"ingredients":["tofu","tomato"],"instructions":["Stir."]}`), http.StatusCreated)
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/sorbet",
+		`{"name":"Lemon sorbet","tags":["quick"],"ingredients":["lemon"],"instructions":["Freeze."]}`), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/tofu", ""), http.StatusOK)
+	checkIndexMatchesScan(t)
+
+	expectStatus(t, serve(router, http.MethodPost, "/admin/reindex", ""), http.StatusUnauthorized)
+	expectStatus(t, serve(router, http.MethodPost, "/admin/reindex", "", "X-API-KEY", "alice-key"), http.StatusOK)
+	checkIndexMatchesScan(t)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IngredientReplaceRequest is the body of POST /admin/ingredients/replace.
+// By default only whole ingredient entries equal to From are replaced;
+// with Substring every occurrence of From inside an entry is.
+type IngredientReplaceRequest struct {
+	From      string `json:"from" binding:"required"`
+	To        string `json:"to" binding:"required"`
+	Substring bool   `json:"substring"`
+}
+
+// IngredientReplaceResponse reports the recipes changed by a replace and
+// the number of replacements made.
+type IngredientReplaceResponse struct {
+	Changed      int      `json:"changed"`
+	Replacements int      `json:"replacements"`
+	IDs          []string `json:"ids"`
+}
+
+// ingredientReplacer returns a function replacing from in one ingredient
+// entry, ignoring case, and reporting how many replacements it made.
+func ingredientReplacer(from, to string, substring bool) func(string) (string, int) {
+	if !substring {
+		return func(entry string) (string, int) {
+			if strings.EqualFold(strings.TrimSpace(entry), from) {
+				return to, 1
+			}
+			return entry, 0
+		}
+	}
+	pattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(from))
+	return func(entry string) (string, int) {
+		n := len(pattern.FindAllStringIndex(entry, -1))
+		if n == 0 {
+			return entry, 0
+		}
+		return pattern.ReplaceAllLiteralString(entry, to), n
+	}
+}
+
+// ReplaceIngredientHandler fixes an ingredient across every recipe, for
+// example after a typo audit. Changed recipes are normalized and validated
+// like an update; if any would be invalid nothing is changed.
+//
+// @Summary Find and replace an ingredient everywhere
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body IngredientReplaceRequest true "Text to find and its replacement"
+// @Success 200 {object} IngredientReplaceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /admin/ingredients/replace [post]
+func ReplaceIngredientHandler(c *gin.Context) {
+	var req IngredientReplaceRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	from, to := strings.TrimSpace(req.From), strings.TrimSpace(req.To)
+	if from == "" || to == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "from and to must not be blank"})
+		return
+	}
+	replace := ingredientReplacer(from, to, req.Substring)
+
+	now := time.Now()
+	resp := IngredientReplaceResponse{IDs: []string{}}
+	recipesMu.Lock()
+	defer recipesMu.Unlock()
+	changed := make(map[int]Recipe)
+	for i, r := range recipes {
+		ingredients := slices.Clone(r.Ingredients)
+		count := 0
+		for j, entry := range ingredients {
+			var n int
+			ingredients[j], n = replace(entry)
+			count += n
+		}
+		if count == 0 {
+			continue
+		}
+		r.Ingredients = ingredients
+		normalizeRecipe(&r)
+		if err := validateRecipe(&r); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("recipe %s: %v", r.ID, err)})
+			return
+		}
+		r.UpdatedAt = Timestamp{now}
+		changed[i] = r
+		resp.Replacements += count
+		resp.IDs = append(resp.IDs, r.ID)
+	}
+	for i, r := range changed {
+		replaceRecipe(i, r)
+		auditor.record(c, "update", r.ID)
+	}
+	resp.Changed = len(resp.IDs)
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func replaceSeed() []Recipe {
+	a := testRecipe("a", "Cake")
+	a.Ingredients = []string{"Tomatoe", "2 cups flour", "tomatoe paste"}
+	b := testRecipe("b", "Salsa")
+	b.Ingredients = []string{"3 TOMATOES", " tomatoe "}
+	c := testRecipe("c", "Bread")
+	c.Ingredients = []string{"flour", "water"}
+	return []Recipe{a, b, c}
+}
+
+func TestReplaceWholeIngredient(t *testing.T) {
+	router := newTestRouter(t, withUsers, replaceSeed()...)
+	body := `{"from":"tomatoe","to":"tomato"}`
+
+	expectStatus(t, serve(router, http.MethodPost, "/admin/ingredients/replace", body), http.StatusUnauthorized)
+
+	w := serve(router, http.MethodPost, "/admin/ingredients/replace", body, "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	resp := decodeBody[IngredientReplaceResponse](t, w)
+	if resp.Changed != 2 || resp.Replacements != 2 || !slices.Equal(resp.IDs, []string{"a", "b"}) {
+		t.Errorf("got %+v, want one whole entry replaced in a and in b", resp)
+	}
+	if got, want := storedRecipe(t, "a").Ingredients, []string{"tomato", "2 cups flour", "tomatoe paste"}; !slices.Equal(got, want) {
+		t.Errorf("a = %q, want %q", got, want)
+	}
+	if got, want := storedRecipe(t, "b").Ingredients, []string{"3 TOMATOES", "tomato"}; !slices.Equal(got, want) {
+		t.Errorf("b = %q, want %q", got, want)
+	}
+	if storedRecipe(t, "a").UpdatedAt.IsZero() || !storedRecipe(t, "c").UpdatedAt.IsZero() {
+		t.Error("want updatedAt bumped on changed recipes only")
+	}
+}
+
+func TestReplaceIngredientSubstring(t *testing.T) {
+	router := newTestRouter(t, withUsers, replaceSeed()...)
+
+	w := serve(router, http.MethodPost, "/admin/ingredients/replace", `{"from":"TOMATOE","to":"tomato","substring":true}`, "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	resp := decodeBody[IngredientReplaceResponse](t, w)
+	if resp.Changed != 2 || resp.Replacements != 4 {
+		t.Errorf("got %+v, want 4 replacements in 2 recipes", resp)
+	}
+	if got, want := storedRecipe(t, "a").Ingredients, []string{"tomato", "2 cups flour", "tomato paste"}; !slices.Equal(got, want) {
+		t.Errorf("a = %q, want %q", got, want)
+	}
+	if got, want := storedRecipe(t, "b").Ingredients, []string{"3 tomatoS", " tomato "}; !slices.Equal(got, want) {
+		t.Errorf("b = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceIngredientErrors(t *testing.T) {
+	router := newTestRouter(t, withUsers, replaceSeed()...)
+	post := func(body string) int {
+		return serve(router, http.MethodPost, "/admin/ingredients/replace", body, "X-API-KEY", "alice-key").Code
+	}
+
+	for _, body := range []string{`{"from":"flour"}`, `{"from":"  ","to":"x"}`} {
+		if code := post(body); code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status %d, want 422", body, code)
+		}
+	}
+	w := serve(router, http.MethodPost, "/admin/ingredients/replace", `{"from":"water","to":"stock"}`, "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[IngredientReplaceResponse](t, w); got.Changed != 1 || got.IDs[0] != "c" {
+		t.Errorf("got %+v, want c changed", got)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IngredientUsage is a distinct ingredient name and how many recipes use it.
+type IngredientUsage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ingredientName reduces an ingredient line to its folded name, dropping
+// any leading quantity and unit: "2 cups Flour" becomes "flour".
+func ingredientName(line string) string {
+	_, _, name, _ := parseIngredient(line)
+	return strings.Join(strings.Fields(foldText(name)), " ")
+}
+
+// IngredientsHandler lists the distinct ingredient names across all recipes
+// with their usage counts, optionally only those starting with ?prefix=.
+//
+// @Summary List distinct ingredients
+// @Tags ingredients
+// @Produce json
+// @Param prefix query string false "Only names starting with this text"
+// @Success 200 {array} IngredientUsage
+// @Router /ingredients [get]
+func IngredientsHandler(c *gin.Context) {
+	prefix := foldText(strings.TrimSpace(c.Query("prefix")))
+
+	counts := make(map[string]int)
+	recipesMu.RLock()
+	for _, r := range recipes {
+		seen := make(map[string]bool, len(r.Ingredients))
+		for _, line := range r.Ingredients {
+			name := ingredientName(line)
+			if name == "" || seen[name] || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			seen[name] = true
+			counts[name]++
+		}
+	}
+	recipesMu.RUnlock()
+
+	out := make([]IngredientUsage, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, IngredientUsage{Name: name, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestIngredientsDedupAndPrefix(t *testing.T) {
+	bread := testRecipe("bread", "Bread")
+	bread.Ingredients = []string{"500 g Flour", "1 tsp salt", "flour"}
+	cake := testRecipe("cake", "Cake")
+	cake.Ingredients = []string{"2 cups flour", "1 cup sugar", "Salt"}
+	router := newTestRouter(t, nil, bread, cake)
+
+	w := serve(router, http.MethodGet, "/ingredients", "")
+	expectStatus(t, w, http.StatusOK)
+	want := []IngredientUsage{{"flour", 2}, {"salt", 2}, {"sugar", 1}}
+	if got := decodeBody[[]IngredientUsage](t, w); !slices.Equal(got, want) {
+		t.Errorf("ingredients = %+v, want %+v", got, want)
+	}
+
+	w = serve(router, http.MethodGet, "/ingredients?prefix=S", "")
+	expectStatus(t, w, http.StatusOK)
+	want = []IngredientUsage{{"salt", 2}, {"sugar", 1}}
+	if got := decodeBody[[]IngredientUsage](t, w); !slices.Equal(got, want) {
+		t.Errorf("prefix=S: %+v, want %+v", got, want)
+	}
+}
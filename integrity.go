@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// integrityIssue is one problem found in loaded data.
+type integrityIssue struct {
+	kind   string
+	detail string
+}
+
+func (i integrityIssue) String() string {
+	return i.kind + ": " + i.detail
+}
+
+// checkIntegrity reports duplicate IDs, recipes missing an ID or a required
+// field, and names used by more than one recipe.
+func checkIntegrity(list []Recipe) []integrityIssue {
+	var issues []integrityIssue
+	ids := make(map[string]int, len(list))
+	names := make(map[string]int, len(list))
+	for i, r := range list {
+		if r.ID == "" {
+			issues = append(issues, integrityIssue{"missing id", fmt.Sprintf("recipe %d has no id", i)})
+		} else if prev, dup := ids[r.ID]; dup {
+			issues = append(issues, integrityIssue{"duplicate id", fmt.Sprintf("recipes %d and %d share id %s", prev, i, r.ID)})
+		} else {
+			ids[r.ID] = i
+		}
+		if missing := missingFields(r); len(missing) > 0 {
+			issues = append(issues, integrityIssue{"empty fields", fmt.Sprintf("recipe %d (%s) has no %s", i, r.ID, strings.Join(missing, ", "))})
+		}
+		name := strings.ToLower(strings.TrimSpace(r.Name))
+		if name == "" {
+			continue
+		}
+		if prev, dup := names[name]; dup {
+			issues = append(issues, integrityIssue{"duplicate name", fmt.Sprintf("recipes %d and %d are both named %q", prev, i, r.Name)})
+		} else {
+			names[name] = i
+		}
+	}
+	return issues
+}
+
+// reportIntegrity logs a summary of issues and, under config.StrictLoad,
+// turns them into an error.
+func reportIntegrity(path string, issues []integrityIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	log.Printf("integrity check of %s found %d issue(s):", path, len(issues))
+	for _, issue := range issues {
+		log.Printf("  %s", issue)
+	}
+	if config.StrictLoad {
+		return fmt.Errorf("%s failed the integrity check with %d issue(s)", path, len(issues))
+	}
+	return nil
+}
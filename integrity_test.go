@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const integrityFixture = `[
+	{"id":"a","name":"Soup","ingredients":["water"],"instructions":["Boil."]},
+	{"id":"a","name":"Stew","ingredients":["beef"],"instructions":["Simmer."]},
+	{"id":"","name":"Bread","ingredients":["flour"],"instructions":["Bake."]},
+	{"id":"c","name":" soup ","ingredients":["leeks"],"instructions":["Blend."]},
+	{"id":"d","name":"","ingredients":[],"instructions":["Stir."]}
+]`
+
+func writeFixture(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recipes.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	path := writeFixture(t, integrityFixture)
+	newTestRouter(t, nil)
+	if err := loadRecipes(path); err != nil {
+		t.Fatalf("lenient load failed: %v", err)
+	}
+	recipesMu.RLock()
+	issues := checkIntegrity(recipes)
+	recipesMu.RUnlock()
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		"duplicate id: recipes 0 and 1 share id a",
+		"missing id: recipe 2 has no id",
+		`duplicate name: recipes 0 and 3 are both named " soup "`,
+		"empty fields: recipe 4 (d) has no name, ingredients",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("issues =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestStrictLoadFailsOnIssues(t *testing.T) {
+	newTestRouter(t, func(cfg *Config) { cfg.StrictLoad = true })
+
+	if err := loadRecipes(writeFixture(t, integrityFixture)); err == nil {
+		t.Error("strict load accepted a file with integrity issues")
+	}
+	if n := len(recipes); n != 0 {
+		t.Errorf("strict load stored %d recipes from a rejected file", n)
+	}
+	if err := loadRecipes(writeFixture(t, `[{"id":"a","name":"Soup","ingredients":["water"],"instructions":["Boil."]}]`)); err != nil {
+		t.Errorf("strict load of a clean file: %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// durationFields are the JSON fields, in minutes, that ?durationFormat=
+// rewrites wherever they appear in a request or response body.
+var durationFields = []string{"prepTime", "cookTime"}
+
+// isoDurationPattern matches the ISO 8601 durations accepted for minute
+// fields: days, hours, minutes and seconds, such as "PT25M" or "P1DT2H".
+// Years, months and weeks are not accepted since they have no fixed length.
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// maxISODurationSeconds is the longest duration accepted, math.MaxInt32
+// minutes, so no sum of components can overflow.
+const maxISODurationSeconds = math.MaxInt32 * 60
+
+// parseISODuration converts an ISO 8601 duration to whole minutes. A
+// duration longer than maxISODurationSeconds is out of range.
+func parseISODuration(s string) (int, error) {
+	m := isoDurationPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil || s == "P" || strings.HasSuffix(strings.ToUpper(s), "T") {
+		return 0, fmt.Errorf("%q is not an ISO 8601 duration such as PT25M", s)
+	}
+	var seconds int64
+	for i, unit := range []int64{86400, 3600, 60, 1} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(m[i+1], 10, 64)
+		if err != nil || n > (maxISODurationSeconds-seconds)/unit {
+			return 0, fmt.Errorf("%q is out of range", s)
+		}
+		seconds += n * unit
+	}
+	if seconds%60 != 0 {
+		return 0, fmt.Errorf("%q is not a whole number of minutes", s)
+	}
+	return int(seconds / 60), nil
+}
+
+// formatISODuration renders minutes as an ISO 8601 duration using hours
+// and minutes, such as "PT1H30M".
+func formatISODuration(minutes int) string {
+	if minutes == 0 {
+		return "PT0M"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := minutes / 60; h > 0 {
+		b.WriteString(strconv.Itoa(h) + "H")
+	}
+	if m := minutes % 60; m > 0 {
+		b.WriteString(strconv.Itoa(m) + "M")
+	}
+	return b.String()
+}
+
+// rewriteDurations walks a decoded JSON value and passes every duration
+// field to fn, replacing it with the result.
+func rewriteDurations(v any, fn func(field string, value any) (any, error)) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if child != nil && isDurationField(k) {
+				out, err := fn(k, child)
+				if err != nil {
+					return err
+				}
+				v[k] = out
+				continue
+			}
+			if err := rewriteDurations(child, fn); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := rewriteDurations(child, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isDurationField(name string) bool {
+	for _, f := range durationFields {
+		if name == f {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJSON decodes body keeping numbers exact as json.Number, where
+// json.Unmarshal would round integers beyond 2^53 to the nearest float64.
+// Like json.Unmarshal it rejects anything after the first value.
+func decodeJSON(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level JSON value")
+	}
+	return doc, nil
+}
+
+// rewriteJSON decodes body, rewrites its duration fields with fn and
+// encodes it again.
+func rewriteJSON(body []byte, fn func(field string, value any) (any, error)) ([]byte, error) {
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := rewriteDurations(doc, fn); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// durationsToMinutes reads ISO 8601 strings in a request; plain numbers are
+// already minutes and pass through.
+func durationsToMinutes(field string, value any) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	minutes, err := parseISODuration(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", field, err)
+	}
+	return minutes, nil
+}
+
+// minutesToDurations writes whole minutes in a response as ISO 8601.
+func minutesToDurations(_ string, value any) (any, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return value, nil
+	}
+	minutes, err := n.Int64()
+	if err != nil {
+		return value, nil
+	}
+	return formatISODuration(int(minutes)), nil
+}
+
+// durationWriter holds back a JSON response so its durations can be
+// rewritten before it is sent. Anything else, such as a CSV or PDF download
+// or a server-sent event stream, is passed straight through. The choice is
+// made at the first write, once the handler has set the Content-Type.
+type durationWriter struct {
+	gin.ResponseWriter
+	head    bool
+	decided bool
+	held    bool
+	status  int
+	body    bytes.Buffer
+}
+
+// decide holds the response back if it is JSON, or otherwise sends the
+// status so the body can follow unbuffered. A JSON response to HEAD is sent
+// as is but loses the Content-Length and ETag of the unrewritten body.
+func (w *durationWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	h := w.Header()
+	if strings.Contains(h.Get("Content-Type"), "json") {
+		if !w.head {
+			w.held = true
+			return
+		}
+		h.Del("Content-Length")
+		h.Del("ETag")
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *durationWriter) WriteHeader(code int) {
+	if !w.decided {
+		w.status = code
+	} else if !w.held {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *durationWriter) WriteHeaderNow() {
+	w.decide()
+	if !w.held {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *durationWriter) Status() int {
+	if !w.decided || w.held {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *durationWriter) Write(b []byte) (int, error) {
+	w.decide()
+	if w.held {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *durationWriter) WriteString(s string) (int, error) {
+	w.decide()
+	if w.held {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *durationWriter) Flush() {
+	w.decide()
+	if !w.held {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend
+// a long poll's write deadline.
+func (w *durationWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// DurationFormatMiddleware implements ?durationFormat=iso8601: prepTime and
+// cookTime are accepted in JSON request bodies as ISO 8601 durations such
+// as "PT25M", and every JSON response renders them that way instead of as
+// minutes. The stored fields stay in minutes.
+func DurationFormatMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Query("durationFormat") {
+		case "":
+			c.Next()
+			return
+		case "iso8601":
+		default:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "durationFormat must be iso8601"})
+			return
+		}
+
+		if strings.HasSuffix(c.ContentType(), "json") && hasBody(c.Request) {
+			limitBody(c)
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.AbortWithStatusJSON(bindStatus(err), gin.H{"error": err.Error()})
+				return
+			}
+			// A body that is not valid JSON is left for the handler to
+			// reject in its usual way.
+			if doc, err := decodeJSON(body); err == nil {
+				if err := rewriteDurations(doc, durationsToMinutes); err != nil {
+					c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				if body, err = json.Marshal(doc); err != nil {
+					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Request.ContentLength = int64(len(body))
+		}
+
+		orig := c.Writer
+		w := &durationWriter{ResponseWriter: orig, head: c.Request.Method == http.MethodHead, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = orig
+		w.decide()
+		if !w.held {
+			return
+		}
+
+		body := w.body.Bytes()
+		if len(body) > 0 {
+			if rewritten, err := rewriteJSON(body, minutesToDurations); err == nil {
+				body = rewritten
+				h := orig.Header()
+				h.Set("Content-Length", strconv.Itoa(len(body)))
+				if h.Get("ETag") != "" {
+					h.Set("ETag", etagFor(body))
+				}
+			}
+		}
+		orig.WriteHeader(w.status)
+		orig.Write(body)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseISODuration(t *testing.T) {
+	for in, want := range map[string]int{
+		"PT25M":    25,
+		"PT1H30M":  90,
+		"pt2h":     120,
+		"P1D":      1440,
+		"P1DT2H":   1560,
+		"PT120S":   2,
+		"PT0M":     0,
+		"PT1H0M0S": 60,
+	} {
+		if got, err := parseISODuration(in); err != nil || got != want {
+			t.Errorf("parseISODuration(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "P", "PT", "25M", "P1W", "P1M", "PT90S", "PT-5M", "PT1.5H",
+		"P99999999999999999D", "P1491309D", "PT2147483647M1S", "PT9223372036854775807S"} {
+		if _, err := parseISODuration(bad); err == nil {
+			t.Errorf("parseISODuration(%q) accepted", bad)
+		}
+	}
+	for minutes, want := range map[int]string{0: "PT0M", 25: "PT25M", 60: "PT1H", 90: "PT1H30M", 1560: "PT26H"} {
+		if got := formatISODuration(minutes); got != want {
+			t.Errorf("formatISODuration(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
+
+func TestISODurationRoundTrip(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	body := `{"name":"Stew","prepTime":"PT25M","cookTime":"PT1H30M","ingredients":["beef"],"instructions":["Simmer."]}`
+	w := serve(router, http.MethodPost, "/recipes?durationFormat=iso8601", body)
+	expectStatus(t, w, http.StatusCreated)
+	created := decodeBody[map[string]any](t, w)
+	if created["prepTime"] != "PT25M" || created["cookTime"] != "PT1H30M" {
+		t.Errorf("response prepTime %v cookTime %v, want ISO durations", created["prepTime"], created["cookTime"])
+	}
+	id := created["id"].(string)
+	if r := storedRecipe(t, id); r.PrepTime != 25 || r.CookTime != 90 {
+		t.Errorf("stored prepTime %d cookTime %d, want 25 and 90 minutes", r.PrepTime, r.CookTime)
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/"+id, "")
+	expectStatus(t, w, http.StatusOK)
+	if r := decodeBody[Recipe](t, w); r.PrepTime != 25 || r.CookTime != 90 {
+		t.Errorf("plain GET prepTime %d cookTime %d, want minutes", r.PrepTime, r.CookTime)
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/"+id+"?durationFormat=iso8601", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[map[string]any](t, w); got["prepTime"] != "PT25M" || got["cookTime"] != "PT1H30M" {
+		t.Errorf("ISO GET prepTime %v cookTime %v", got["prepTime"], got["cookTime"])
+	}
+
+	// Lists are rewritten too, and minutes are still accepted.
+	w = serve(router, http.MethodPut, "/recipe/"+id+"?durationFormat=iso8601",
+		`{"name":"Stew","prepTime":10,"ingredients":["beef"],"instructions":["Simmer."]}`)
+	expectStatus(t, w, http.StatusOK)
+	w = serve(router, http.MethodGet, "/recipes?durationFormat=iso8601", "")
+	expectStatus(t, w, http.StatusOK)
+	list := decodeBody[Page[map[string]any]](t, w).Data
+	if len(list) != 1 || list[0]["prepTime"] != "PT10M" {
+		t.Errorf("list = %v, want prepTime PT10M", list)
+	}
+}
+
+func TestISODurationErrors(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	expectStatus(t, serve(router, http.MethodGet, "/recipes?durationFormat=minutes", ""), http.StatusBadRequest)
+	w := serve(router, http.MethodPost, "/recipes?durationFormat=iso8601",
+		`{"name":"Stew","prepTime":"25 minutes","ingredients":["beef"],"instructions":["Simmer."]}`)
+	expectStatus(t, w, http.StatusBadRequest)
+	if n := len(recipes); n != 0 {
+		t.Errorf("stored %d recipes after a bad duration", n)
+	}
+}
+
+func TestISODurationLimits(t *testing.T) {
+	if got, err := parseISODuration("PT2147483647M"); err != nil || got != 2147483647 {
+		t.Errorf("parseISODuration at the limit = %d, %v", got, err)
+	}
+	if _, err := parseISODuration("P99999999999999999D"); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("huge duration error = %v, want out of range", err)
+	}
+
+	router := newTestRouter(t, nil)
+	w := serve(router, http.MethodPost, "/recipes?durationFormat=iso8601",
+		`{"name":"`+strings.Repeat("a", maxJSONBytes)+`"}`)
+	expectStatus(t, w, http.StatusRequestEntityTooLarge)
+	if n := len(recipes); n != 0 {
+		t.Errorf("stored %d recipes from an oversized body", n)
+	}
+}
+
+func TestISODurationPassesThroughNonJSON(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	plain := serve(router, http.MethodGet, "/recipes/export.csv", "")
+	w := serve(router, http.MethodGet, "/recipes/export.csv?durationFormat=iso8601", "")
+	expectStatus(t, w, http.StatusOK)
+	if w.Body.String() != plain.Body.String() {
+		t.Errorf("CSV export changed under durationFormat:\n%s\nwant\n%s", w.Body, plain.Body)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", got)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// commonIngredients is the vocabulary the linter recognises in instruction
+// text. It is deliberately small: words outside it are never flagged.
+var commonIngredients = map[string]bool{
+	"apple": true, "bacon": true, "basil": true, "bean": true, "beef": true,
+	"broth": true, "butter": true, "carrot": true, "cheese": true, "chicken": true,
+	"chili": true, "chocolate": true, "cinnamon": true, "cream": true, "egg": true,
+	"fish": true, "flour": true, "garlic": true, "ginger": true, "honey": true,
+	"lemon": true, "lime": true, "milk": true, "mushroom": true, "noodle": true,
+	"oil": true, "onion": true, "parsley": true, "pasta": true, "pepper": true,
+	"pork": true, "potato": true, "rice": true, "salt": true, "shrimp": true,
+	"spinach": true, "stock": true, "sugar": true, "tofu": true, "tomato": true,
+	"vanilla": true, "vinegar": true, "water": true, "wine": true, "yeast": true,
+	"yogurt": true,
+}
+
+// singular strips common English plural endings.
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "oes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// ingredientWords returns the folded, singular words of text.
+func ingredientWords(text string) []string {
+	fields := strings.FieldsFunc(foldText(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for i, f := range fields {
+		fields[i] = singular(f)
+	}
+	return fields
+}
+
+// lintRecipe warns about instruction steps mentioning a known ingredient that
+// does not appear in the recipe's ingredient list.
+func lintRecipe(r Recipe) []string {
+	listed := make(map[string]bool)
+	for _, line := range r.Ingredients {
+		for _, w := range ingredientWords(line) {
+			listed[w] = true
+		}
+	}
+	warnings := make([]string, 0)
+	reported := make(map[string]bool)
+	for step, text := range r.Instructions {
+		for _, w := range ingredientWords(text) {
+			if !commonIngredients[w] || listed[w] || reported[w] {
+				continue
+			}
+			reported[w] = true
+			warnings = append(warnings, fmt.Sprintf("instruction %d mentions %q, which is not in ingredients", step+1, w))
+		}
+	}
+	return warnings
+}
+
+// LintedRecipe is a saved recipe returned together with lint warnings.
+type LintedRecipe struct {
+	Recipe
+	Warnings []string `json:"warnings"`
+}
+
+// IncompleteRecipe is a recipe annotated with the key fields it lacks.
+type IncompleteRecipe struct {
+	Recipe
+	Missing []string `json:"missing"`
+}
+
+// hasContent reports whether values has at least one non-blank entry.
+func hasContent(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// missingFields lists which of name, ingredients and instructions r lacks.
+func missingFields(r Recipe) []string {
+	var missing []string
+	if strings.TrimSpace(r.Name) == "" {
+		missing = append(missing, "name")
+	}
+	if !hasContent(r.Ingredients) {
+		missing = append(missing, "ingredients")
+	}
+	if !hasContent(r.Instructions) {
+		missing = append(missing, "instructions")
+	}
+	return missing
+}
+
+// IncompleteRecipesHandler lists recipes with an empty name, no ingredients
+// or no instructions.
+//
+// @Summary List incomplete recipes
+// @Tags recipes
+// @Produce json
+// @Success 200 {array} IncompleteRecipe
+// @Router /recipes/incomplete [get]
+func IncompleteRecipesHandler(c *gin.Context) {
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	out := make([]IncompleteRecipe, 0)
+	for _, r := range recipes {
+		if missing := missingFields(r); len(missing) > 0 {
+			out = append(out, IncompleteRecipe{Recipe: fresh(r), Missing: missing})
+		}
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestLintWarnsAboutMissingIngredient(t *testing.T) {
+	router := newTestRouter(t, nil)
+	body := `{"name":"Pasta","ingredients":["200 g pasta","2 tomatoes"],"instructions":["Boil the pasta.","Stir in the tomatoes and garlic."]}`
+
+	w := serve(router, http.MethodPost, "/recipes?lint=true", body)
+	expectStatus(t, w, http.StatusCreated)
+	got := decodeBody[LintedRecipe](t, w)
+	want := []string{`instruction 2 mentions "garlic", which is not in ingredients`}
+	if !slices.Equal(got.Warnings, want) {
+		t.Errorf("warnings = %q, want %q", got.Warnings, want)
+	}
+	storedRecipe(t, got.ID)
+
+	w = serve(router, http.MethodPost, "/recipes", body)
+	expectStatus(t, w, http.StatusCreated)
+	if _, ok := decodeBody[map[string]any](t, w)["warnings"]; ok {
+		t.Error("warnings returned without ?lint=true")
+	}
+}
+
+func TestIncompleteRecipes(t *testing.T) {
+	noName := testRecipe("noName", " ")
+	bare := Recipe{ID: "bare", Name: "Bare", Ingredients: []string{""}}
+	noSteps := testRecipe("noSteps", "No steps")
+	noSteps.Instructions = nil
+	router := newTestRouter(t, nil, testRecipe("complete", "Complete"), noName, bare, noSteps)
+
+	w := serve(router, http.MethodGet, "/recipes/incomplete", "")
+	expectStatus(t, w, http.StatusOK)
+	got := make(map[string][]string)
+	for _, r := range decodeBody[[]IncompleteRecipe](t, w) {
+		got[r.ID] = r.Missing
+	}
+	want := map[string][]string{
+		"noName":  {"name"},
+		"bare":    {"ingredients", "instructions"},
+		"noSteps": {"instructions"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("flagged %v, want %v", got, want)
+	}
+	for id, missing := range want {
+		if !slices.Equal(got[id], missing) {
+			t.Errorf("%s missing = %q, want %q", id, got[id], missing)
+		}
+	}
+}
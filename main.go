@@ -1,8 +1,181 @@
 package main
 
-import "github.com/gin-gonic/gin"
+import (
+	"log"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"golang.org/x/time/rate"
+)
+
+func setupRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Logger())
+	// Error pages wrap recovery so that a panic's 500 is rendered for
+	// browsers like any other error.
+	if config.HTMLErrors {
+		router.Use(HTMLErrorMiddleware())
+	}
+	router.Use(gin.CustomRecovery(RecoveryHandler))
+	// API clients get exact path matching: a request with a stray trailing
+	// slash or wrong case is a 404 JSON error rather than a 301/307 redirect,
+	// which some clients follow by dropping the request body.
+	router.RedirectTrailingSlash = false
+	router.RedirectFixedPath = false
+	router.NoRoute(NotFoundHandler)
+	router.Use(MetricsMiddleware())
+	router.Use(SecurityHeadersMiddleware(config.SecurityHeaders))
+	if config.ForceHTTPS {
+		router.Use(ForceHTTPSMiddleware())
+	}
+	router.Use(CORSMiddleware(config.CORS))
+	router.Use(MaxQueryMiddleware(config.Server.MaxQueryBytes))
+	// Rate limiting comes first so that requests AuthMiddleware rejects for
+	// an invalid key are still counted.
+	if config.RateLimit.RPS > 0 || config.RateLimit.AuthRPS > 0 {
+		router.Use(newRequestRateLimiter(config.RateLimit, config.APIKeys).Middleware())
+	}
+	router.Use(AuthMiddleware(config.APIKeys))
+	router.Use(DurationFormatMiddleware())
+
+	writeTypes := RequireContentType(config.WriteContentTypes...)
+	jsonOnly := RequireContentType("application/json")
+
+	router.POST("/recipes", writeTypes, NewRecipeHandler)
+	router.GET("/recipes", ListRecipesHandler)
+	router.HEAD("/recipes", ListRecipesHandler)
+	router.GET("/recipe/:id", GetRecipeHandler)
+	router.HEAD("/recipe/:id", GetRecipeHandler)
+	router.PUT("/recipe/:id", writeTypes, UpdateRecipeHandler)
+	router.PATCH("/recipe/:id", RequireContentType(mimeMergePatch), MergePatchRecipeHandler)
+	router.DELETE("/recipe/:id", DeleteRecipeHandler)
+	router.GET("/recipes/incomplete", IncompleteRecipesHandler)
+	router.GET("/recipes/duplicates", DuplicateRecipesHandler)
+
+	// Optional route groups; see knownFeatures. Routes of disabled features
+	// are not registered, so they 404 like any unknown path.
+	features := config.Features
+	if features.on("images") {
+		router.POST("/recipe/:id/image", RequireAuth(), UploadImageHandler)
+	}
+	if features.on("clone") {
+		router.POST("/recipe/:id/clone", CloneRecipeHandler)
+		router.POST("/recipe/:id/fork", ForkRecipeHandler)
+		router.GET("/recipe/:id/forks", ListForksHandler)
+	}
+	if features.on("pins") {
+		router.POST("/recipe/:id/pin", RequireAuth(), PinRecipeHandler)
+		router.POST("/recipe/:id/unpin", RequireAuth(), UnpinRecipeHandler)
+	}
+	if features.on("favorites") {
+		router.POST("/recipe/:id/favorite", RequireAuth(), AddFavoriteHandler)
+		router.DELETE("/recipe/:id/favorite", RequireAuth(), RemoveFavoriteHandler)
+		router.GET("/recipes/favorites", RequireAuth(), ListFavoritesHandler)
+		router.GET("/recipes/most-favorited", MostFavoritedHandler)
+	}
+	if features.on("kitchen") {
+		router.GET("/recipe/:id/scale", ScaleRecipeHandler)
+		router.GET("/recipe/:id/estimate-difficulty", EstimateDifficultyHandler)
+		router.GET("/recipe/:id/timers", TimersHandler)
+		router.GET("/recipe/:id/quantity-check", QuantityCheckHandler)
+		router.GET("/recipe/:id/reorder-ingredients", ReorderIngredientsHandler)
+		router.GET("/recipe/:id/nutrition/estimate", NutritionEstimateHandler)
+		router.POST("/recipe/:id/cook-batch", CookBatchHandler)
+		router.GET("/batches", ListBatchesHandler)
+		router.POST("/batches/:id/consume", ConsumeBatchHandler)
+		router.POST("/shopping-list/scaled", ScaledShoppingListHandler)
+	}
+	if features.on("batch") {
+		router.POST("/recipes/batch", jsonOnly, BatchCreateRecipesHandler)
+		router.POST("/recipes/batch-get", BatchGetRecipesHandler)
+		router.PATCH("/recipes/batch", jsonOnly, BatchPatchRecipesHandler)
+	}
+	if features.on("search") {
+		router.GET("/recipes/search", SearchRecipesHandler)
+		router.GET("/recipes/search/text", TextSearchRecipesHandler)
+		router.POST("/recipes/multi-search", jsonOnly, MultiSearchHandler)
+		router.GET("/recipes/facets", FacetsHandler)
+		router.GET("/recipe/:id/similar", SimilarRecipesHandler)
+	}
+	if features.on("history") {
+		router.GET("/recipes/recent", RequireAuth(), RecentRecipesHandler)
+	}
+	if features.on("trending") {
+		router.GET("/recipes/trending", TrendingRecipesHandler)
+	}
+	if features.on("schema") {
+		router.GET("/recipes/schema", RecipeSchemaHandler)
+	}
+	if features.on("export") {
+		router.GET("/recipes/export.csv", ExportCSVHandler)
+		router.GET("/recipes/export.json", ExportJSONHandler)
+		router.GET("/recipes/flat", FlatRecipesHandler)
+		router.GET("/recipes/feed.atom", RecipeFeedHandler)
+		router.GET("/recipe/:id/export.pdf", ExportPDFHandler)
+		router.GET("/recipe/:id/qr", RecipeQRHandler)
+	}
+	if features.on("import") {
+		router.PUT("/recipes", RequireAuth(), jsonOnly, ReplaceRecipesHandler)
+		router.POST("/recipes/import.csv", RequireAuth(), ImportCSVHandler)
+	}
+	if features.on("sync") {
+		router.GET("/recipes/ids", RecipeIDsHandler)
+		router.GET("/recipes/changes", RecipeChangesHandler)
+		router.GET("/recipes/poll", PollChangesHandler)
+	}
+	if features.on("menu") {
+		router.POST("/menu/suggest", MenuSuggestHandler)
+		router.POST("/meal-plan/generate", jsonOnly, GenerateMealPlanHandler)
+	}
+	if features.on("ingredients") {
+		router.GET("/ingredients", IngredientsHandler)
+	}
+	if features.on("admin") {
+		admin := router.Group("/admin", RequireAuth())
+		admin.POST("/reindex", newRateLimiter(rate.Every(time.Minute), 1).Middleware(), ReindexHandler)
+		admin.POST("/warmup", WarmupHandler)
+		admin.POST("/backfill-timestamps", BackfillTimestampsHandler)
+		admin.POST("/normalize", NormalizeHandler)
+		admin.POST("/ingredients/replace", ReplaceIngredientHandler)
+	}
+	if features.on("metrics") {
+		router.GET("/metrics", MetricsHandler)
+	}
+	if features.on("docs") {
+		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+	return router
+}
+
+// @title Recipes API
+// @version 1.0.0
+// @description A small API for storing, searching and scaling recipes.
+// @host localhost:7778
+// @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-KEY
 func main() {
-	router := gin.Default()
-	router.Run(":7778")
+	if err := configFromEnv(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	if err := loadContentFilter(config.ContentFilter); err != nil {
+		log.Fatalf("content filter: %v", err)
+	}
+	if err := loadAutoTags(config.AutoTag); err != nil {
+		log.Fatalf("auto-tag rules: %v", err)
+	}
+	if err := loadNutritionTable(config.NutritionTable); err != nil {
+		log.Fatalf("nutrition table: %v", err)
+	}
+	if err := loadRecipes(config.RecipesFile); err != nil {
+		log.Fatalf("loading recipes: %v", err)
+	}
+	srv := newServer(setupRouter(), config.Port, config.Server)
+	log.Printf("listening on %s", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// newTestRouter resets the store and every other piece of package state to
+// that of a freshly started server holding seed, applies the default
+// configuration as changed by configure (which may be nil) and returns the
+// router. Tests share package state, so none of them run in parallel.
+func newTestRouter(t testing.TB, configure func(*Config), seed ...Recipe) *gin.Engine {
+	t.Helper()
+	cfg := defaultConfig()
+	if configure != nil {
+		configure(&cfg)
+	}
+	applyConfig(cfg)
+	t.Cleanup(func() { applyConfig(defaultConfig()) })
+
+	clock = time.Now
+	for _, h := range metricsRegistry {
+		h.series = make(map[string]*histogramSeries)
+	}
+	autoTags = autoTagger{}
+	bannedWords = contentFilter{}
+	nutritionTable = nil
+	favorites = &favoriteStore{byUser: make(map[string]map[string]time.Time)}
+	recentlyViewed = newRecentViews()
+	recipeViews = newViewCounter(viewBucketSize, viewRetention)
+	cookBatches = &batchStore{batches: make(map[string]*CookBatch)}
+	pendingDeletes = &deleteConfirmations{tokens: make(map[string]deleteToken)}
+
+	recipesMu.Lock()
+	replaceAllRecipes(append([]Recipe(nil), seed...))
+	tombstones, tombstoneHorizon = nil, time.Time{}
+	recipesMu.Unlock()
+	return setupRouter()
+}
+
+// testRecipe is a minimal valid recipe with the given ID, name and tags.
+func testRecipe(id, name string, tags ...string) Recipe {
+	return Recipe{
+		ID:           id,
+		Name:         name,
+		Tags:         tags,
+		Ingredients:  []string{"1 cup flour"},
+		Instructions: []string{"Mix the flour."},
+	}
+}
+
+// serve sends a request to router and returns the recorded response. A
+// non-empty body is sent as JSON unless headers, given as name/value pairs,
+// set another Content-Type.
+func serve(router http.Handler, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, r)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decodeBody decodes a JSON response body, failing the test if it is not
+// valid JSON of type T.
+func decodeBody[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decoding %q: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+// expectStatus fails the test unless w has the wanted status.
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if w.Code != want {
+		t.Fatalf("status = %d, want %d; body %s", w.Code, want, w.Body.String())
+	}
+}
+
+// storedRecipe returns the stored recipe with id, failing the test if there
+// is none.
+func storedRecipe(t *testing.T, id string) Recipe {
+	t.Helper()
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	i := findRecipe(id)
+	if i < 0 {
+		t.Fatalf("recipe %s not stored", id)
+	}
+	return recipes[i]
+}
+
+// withUsers configures the API keys "alice-key" and "bob-key" for the users
+// alice and bob.
+func withUsers(cfg *Config) {
+	cfg.APIKeys = map[string]string{"alice-key": "alice", "bob-key": "bob"}
+}
+
+// listIDs fetches a page of recipes from target and returns their IDs in
+// order.
+func listIDs(t *testing.T, router http.Handler, target string, headers ...string) []string {
+	t.Helper()
+	w := serve(router, http.MethodGet, target, "", headers...)
+	expectStatus(t, w, http.StatusOK)
+	ids := make([]string, 0)
+	for _, r := range decodeBody[PaginatedRecipes](t, w).Data {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestTrailingSlashIsNotRedirected(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	expectStatus(t, serve(router, http.MethodGet, "/recipes", ""), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/r1", ""), http.StatusOK)
+	for _, tc := range []struct{ method, target, body string }{
+		{http.MethodGet, "/recipes/", ""},
+		{http.MethodGet, "/recipe/r1/", ""},
+		{http.MethodPost, "/recipes/", `{"name":"Stew"}`},
+		{http.MethodGet, "/RECIPES", ""},
+	} {
+		w := serve(router, tc.method, tc.target, tc.body)
+		expectStatus(t, w, http.StatusNotFound)
+		if loc := w.Header().Get("Location"); loc != "" {
+			t.Errorf("%s %s redirected to %s", tc.method, tc.target, loc)
+		}
+		if got := decodeBody[ErrorResponse](t, w).Error; got != "route not found" {
+			t.Errorf("%s %s error = %q, want route not found", tc.method, tc.target, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MealPlanRequest is the body of POST /meal-plan/generate. Tags must all be
+// carried by a pick, and with MaxTotalMinutes a pick's prepTime plus
+// cookTime must be known and within it. Picks do not repeat unless
+// AllowRepeats is set, in which case each recipe is used once before any is
+// used again. A Seed makes the plan reproducible.
+type MealPlanRequest struct {
+	Days            int      `json:"days" binding:"required,min=1,max=31"`
+	Tags            []string `json:"tags"`
+	Category        string   `json:"category"`
+	MaxTotalMinutes int      `json:"maxTotalMinutes" binding:"min=0"`
+	AllowRepeats    bool     `json:"allowRepeats"`
+	Seed            *uint64  `json:"seed"`
+}
+
+// MealPlanDay is the recipe picked for one day of a plan, counted from 1.
+type MealPlanDay struct {
+	Day    int    `json:"day"`
+	Recipe Recipe `json:"recipe"`
+}
+
+// MealPlanResponse is a generated meal plan.
+type MealPlanResponse struct {
+	Days []MealPlanDay `json:"days"`
+	// Candidates is how many recipes met the constraints.
+	Candidates int `json:"candidates"`
+}
+
+// matches reports whether r meets the constraints of req.
+func (req MealPlanRequest) matches(r Recipe) bool {
+	if req.Category != "" && r.Category != req.Category {
+		return false
+	}
+	for _, t := range req.Tags {
+		if !recipeHasTag(r, t) {
+			return false
+		}
+	}
+	if req.MaxTotalMinutes > 0 {
+		total := r.PrepTime + r.CookTime
+		if total == 0 || total > req.MaxTotalMinutes {
+			return false
+		}
+	}
+	return true
+}
+
+// planMeals picks one recipe per day from pool in random order, drawing
+// the whole pool before starting over when the plan is longer than it.
+func planMeals(pool []Recipe, days int, rng *rand.Rand) []MealPlanDay {
+	out := make([]MealPlanDay, 0, days)
+	var order []int
+	for day := 1; day <= days; day++ {
+		if len(order) == 0 {
+			order = rng.Perm(len(pool))
+		}
+		out = append(out, MealPlanDay{Day: day, Recipe: fresh(pool[order[0]])})
+		order = order[1:]
+	}
+	return out
+}
+
+// GenerateMealPlanHandler picks a recipe for each of the requested days
+// from the recipes meeting the constraints. Without allowRepeats there must
+// be at least one matching recipe per day.
+//
+// @Summary Generate a meal plan
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param request body MealPlanRequest true "Plan constraints"
+// @Success 200 {object} MealPlanResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /meal-plan/generate [post]
+func GenerateMealPlanHandler(c *gin.Context) {
+	var req MealPlanRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if req.Category != "" && !knownCategories[req.Category] {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("unknown category %q", req.Category)})
+		return
+	}
+	req.Tags = normalizeTags(req.Tags)
+
+	var pool []Recipe
+	recipesMu.RLock()
+	for _, r := range recipes {
+		if req.matches(r) {
+			pool = append(pool, r)
+		}
+	}
+	recipesMu.RUnlock()
+
+	switch {
+	case len(pool) == 0:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "no recipes match the constraints"})
+		return
+	case len(pool) < req.Days && !req.AllowRepeats:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("only %d recipes match the constraints, fewer than the %d days requested; relax them or set allowRepeats", len(pool), req.Days)})
+		return
+	}
+	seed := rand.Uint64()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+	c.JSON(http.StatusOK, MealPlanResponse{Days: planMeals(pool, req.Days, rng), Candidates: len(pool)})
+}
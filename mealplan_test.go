@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// mealPlanSeed has three quick vegan dinners, a slow vegan one, one without
+// timings and a quick one with meat.
+func mealPlanSeed() []Recipe {
+	var seed []Recipe
+	for _, r := range []struct {
+		id         string
+		prep, cook int
+		tags       []string
+	}{
+		{"salad", 10, 0, []string{"vegan"}},
+		{"stirfry", 10, 15, []string{"vegan"}},
+		{"soup", 15, 20, []string{"vegan"}},
+		{"stew", 30, 120, []string{"vegan"}},
+		{"curry", 0, 0, []string{"vegan"}},
+		{"steak", 5, 10, []string{"meat"}},
+	} {
+		recipe := testRecipe(r.id, r.id, r.tags...)
+		recipe.PrepTime, recipe.CookTime = r.prep, r.cook
+		seed = append(seed, recipe)
+	}
+	return seed
+}
+
+func TestGenerateMealPlan(t *testing.T) {
+	router := newTestRouter(t, nil, mealPlanSeed()...)
+
+	w := serve(router, http.MethodPost, "/meal-plan/generate", `{"days":3,"tags":["Vegan"],"maxTotalMinutes":60,"seed":1}`)
+	expectStatus(t, w, http.StatusOK)
+	plan := decodeBody[MealPlanResponse](t, w)
+	if plan.Candidates != 3 || len(plan.Days) != 3 {
+		t.Fatalf("got %d days from %d candidates, want 3 from 3", len(plan.Days), plan.Candidates)
+	}
+	seen := make(map[string]bool)
+	for i, d := range plan.Days {
+		if d.Day != i+1 {
+			t.Errorf("day %d numbered %d", i+1, d.Day)
+		}
+		if id := d.Recipe.ID; id != "salad" && id != "stirfry" && id != "soup" {
+			t.Errorf("day %d picked %s, which breaks the constraints", d.Day, id)
+		}
+		if seen[d.Recipe.ID] {
+			t.Errorf("%s picked twice", d.Recipe.ID)
+		}
+		seen[d.Recipe.ID] = true
+	}
+
+	again := serve(router, http.MethodPost, "/meal-plan/generate", `{"days":3,"tags":["Vegan"],"maxTotalMinutes":60,"seed":1}`)
+	if again.Body.String() != w.Body.String() {
+		t.Errorf("same seed gave %s, then %s", w.Body.String(), again.Body.String())
+	}
+}
+
+func TestGenerateMealPlanRepeatsOnlyWhenAllowed(t *testing.T) {
+	router := newTestRouter(t, nil, mealPlanSeed()...)
+
+	w := serve(router, http.MethodPost, "/meal-plan/generate", `{"days":7,"tags":["vegan"],"maxTotalMinutes":60}`)
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+	if got := decodeBody[ErrorResponse](t, w).Error; !strings.HasPrefix(got, "only 3 recipes match") {
+		t.Errorf("error = %q, want it to say only 3 recipes match", got)
+	}
+
+	w = serve(router, http.MethodPost, "/meal-plan/generate", `{"days":7,"tags":["vegan"],"maxTotalMinutes":60,"allowRepeats":true}`)
+	expectStatus(t, w, http.StatusOK)
+	days := decodeBody[MealPlanResponse](t, w).Days
+	// Each recipe is used once before any is used again.
+	for start := 0; start+3 <= len(days); start += 3 {
+		seen := make(map[string]bool)
+		for _, d := range days[start : start+3] {
+			if seen[d.Recipe.ID] {
+				t.Errorf("%s repeated within days %d-%d", d.Recipe.ID, start+1, start+3)
+			}
+			seen[d.Recipe.ID] = true
+		}
+	}
+
+	w = serve(router, http.MethodPost, "/meal-plan/generate", `{"days":1,"tags":["dessert"]}`)
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+	expectStatus(t, serve(router, http.MethodPost, "/meal-plan/generate", `{"days":0}`), http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// menuCourses are the categories suggested around a main course, in menu
+// order.
+var menuCourses = []string{"starter", "side", "dessert"}
+
+// difficultyRank orders the known difficulties from easiest.
+var difficultyRank = map[string]int{"easy": 1, "medium": 2, "hard": 3}
+
+// MenuSuggestRequest is the body of POST /menu/suggest.
+type MenuSuggestRequest struct {
+	MainID string `json:"mainId" binding:"required"`
+}
+
+// MenuSuggestion is the best match for one course, or nil when no recipe
+// has that category.
+type MenuSuggestion struct {
+	Course     string  `json:"course"`
+	Recipe     *Recipe `json:"recipe"`
+	SharedTags int     `json:"sharedTags"`
+}
+
+// MenuSuggestResponse is a main course with a suggestion for each course.
+type MenuSuggestResponse struct {
+	Main        Recipe           `json:"main"`
+	Suggestions []MenuSuggestion `json:"suggestions"`
+}
+
+// recipeDifficulty is r's stated difficulty, or its estimate if it has none.
+func recipeDifficulty(r Recipe) string {
+	if r.Difficulty != "" {
+		return r.Difficulty
+	}
+	return estimateDifficulty(r).Difficulty
+}
+
+// pairingScore rates candidate as a course alongside main: one point per
+// shared tag, less one for each step the pair's combined difficulty goes
+// beyond medium plus easy, so a hard main pulls in easy courses.
+func pairingScore(main, candidate Recipe) (score, shared int) {
+	for _, t := range candidate.Tags {
+		if containsString(main.Tags, t) {
+			shared++
+		}
+	}
+	excess := difficultyRank[recipeDifficulty(main)] + difficultyRank[recipeDifficulty(candidate)] - 3
+	return shared - max(excess, 0), shared
+}
+
+// suggestMenu picks the best-scoring recipe of each course for main. Ties go
+// to the recipe listed first in the store. Callers must hold recipesMu.
+func suggestMenu(main Recipe) []MenuSuggestion {
+	out := make([]MenuSuggestion, 0, len(menuCourses))
+	for _, course := range menuCourses {
+		s := MenuSuggestion{Course: course}
+		best := 0
+		for i := range recipes {
+			r := recipes[i]
+			if r.Category != course || r.ID == main.ID {
+				continue
+			}
+			score, shared := pairingScore(main, r)
+			if s.Recipe == nil || score > best {
+				r = fresh(r)
+				s.Recipe, s.SharedTags, best = &r, shared, score
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// MenuSuggestHandler builds a three-course menu around a main course,
+// suggesting a starter, a side and a dessert that share its tags and
+// balance its difficulty.
+//
+// @Summary Suggest a menu around a main course
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param request body MenuSuggestRequest true "Main course"
+// @Success 200 {object} MenuSuggestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /menu/suggest [post]
+func MenuSuggestHandler(c *gin.Context) {
+	var req MenuSuggestRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	i := findRecipe(req.MainID)
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	c.JSON(http.StatusOK, MenuSuggestResponse{Main: fresh(recipes[i]), Suggestions: suggestMenu(recipes[i])})
+}
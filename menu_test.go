@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMenuSuggest(t *testing.T) {
+	course := func(id, category, difficulty string, tags ...string) Recipe {
+		r := testRecipe(id, id, tags...)
+		r.Category, r.Difficulty = category, difficulty
+		return r
+	}
+	router := newTestRouter(t, nil,
+		course("lasagne", "main", "hard", "italian", "baked"),
+		course("other-main", "main", "easy", "italian"),
+		course("plain-starter", "starter", "easy"),
+		course("bruschetta", "starter", "easy", "italian"),
+		course("fancy-side", "side", "hard", "italian"),
+		course("salad", "side", "easy"),
+		course("tiramisu", "dessert", "medium", "italian"),
+	)
+
+	w := serve(router, http.MethodPost, "/menu/suggest", `{"mainId":"lasagne"}`)
+	expectStatus(t, w, http.StatusOK)
+	resp := decodeBody[MenuSuggestResponse](t, w)
+	if resp.Main.ID != "lasagne" {
+		t.Errorf("main = %s, want lasagne", resp.Main.ID)
+	}
+	want := []struct{ course, id string }{
+		{"starter", "bruschetta"},
+		// The hard side shares a tag but would make the meal too hard.
+		{"side", "salad"},
+		{"dessert", "tiramisu"},
+	}
+	if len(resp.Suggestions) != len(want) {
+		t.Fatalf("got %d suggestions, want %d", len(resp.Suggestions), len(want))
+	}
+	for i, s := range resp.Suggestions {
+		if s.Course != want[i].course || s.Recipe == nil || s.Recipe.ID != want[i].id {
+			t.Errorf("suggestion %d = %+v, want %s %s", i, s, want[i].course, want[i].id)
+			continue
+		}
+		if s.Recipe.Category != s.Course {
+			t.Errorf("%s suggestion %s has category %q", s.Course, s.Recipe.ID, s.Recipe.Category)
+		}
+	}
+}
+
+func TestMenuSuggestMissingCourseAndMain(t *testing.T) {
+	main := testRecipe("stew", "Stew")
+	main.Category = "main"
+	router := newTestRouter(t, nil, main)
+
+	w := serve(router, http.MethodPost, "/menu/suggest", `{"mainId":"stew"}`)
+	expectStatus(t, w, http.StatusOK)
+	for _, s := range decodeBody[MenuSuggestResponse](t, w).Suggestions {
+		if s.Recipe != nil {
+			t.Errorf("%s suggestion = %s, want none", s.Course, s.Recipe.ID)
+		}
+	}
+
+	expectStatus(t, serve(router, http.MethodPost, "/menu/suggest", `{"mainId":"missing"}`), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodPost, "/menu/suggest", `{}`), http.StatusUnprocessableEntity)
+}
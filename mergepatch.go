@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mimeMergePatch is the media type of an RFC 7396 JSON Merge Patch.
+const mimeMergePatch = "application/merge-patch+json"
+
+// mergePatch applies an RFC 7396 merge patch to target: a null member
+// removes the field, an object member is merged recursively and any other
+// member replaces the field. A patch that is not an object replaces target.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// mergePatchRecipe returns r with patch applied. It fails when the patch
+// is not a JSON object or the result no longer decodes as a recipe; in
+// strict mode unknown fields are rejected by name.
+func mergePatchRecipe(r Recipe, patch map[string]any, strict bool) (Recipe, error) {
+	current, err := json.Marshal(r)
+	if err != nil {
+		return Recipe{}, err
+	}
+	doc, err := decodeJSON(current)
+	if err != nil {
+		return Recipe{}, err
+	}
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return Recipe{}, err
+	}
+	var out Recipe
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&out); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return Recipe{}, fmt.Errorf("unknown field %s", field)
+		}
+		return Recipe{}, numberError(err)
+	}
+	if _, ok := patch["steps"]; ok {
+		if _, ok := patch["instructions"]; !ok {
+			// Let normalizeRecipe derive instructions from the new steps.
+			out.Instructions = nil
+		}
+	}
+	return out, nil
+}
+
+// MergePatchRecipeHandler updates a recipe with an RFC 7396 JSON Merge
+// Patch: fields in the patch replace the recipe's, fields set to null are
+// cleared and absent fields are left alone. Unlike PATCH /recipes/batch it
+// can clear a field. The ID, timestamps, thumbnail and pin cannot be
+// patched.
+//
+// @Summary Merge-patch a recipe
+// @Tags recipes
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param patch body object true "JSON Merge Patch"
+// @Param lint query bool false "Return warnings for instructions mentioning unlisted ingredients"
+// @Success 200 {object} Recipe
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /recipe/{id} [patch]
+func MergePatchRecipeHandler(c *gin.Context) {
+	if !hasBody(c.Request) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errEmptyBody.Error()})
+		return
+	}
+	limitBody(c)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// Numbers stay json.Number through the merge so large integers are
+	// not rounded on the way to the recipe's int fields.
+	doc, err := decodeJSON(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	patch, ok := doc.(map[string]any)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "merge patch must be a JSON object"})
+		return
+	}
+
+	recipesMu.Lock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe, err := mergePatchRecipe(recipes[i], patch, strictJSON(c))
+	if err != nil {
+		recipesMu.Unlock()
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	normalizeRecipe(&recipe)
+	if err := validateRecipe(&recipe); err != nil {
+		recipesMu.Unlock()
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	recipe.ID = recipes[i].ID
+	recipe.PublishedAt = recipes[i].PublishedAt
+	recipe.Thumbnail = recipes[i].Thumbnail
+	recipe.Pinned = recipes[i].Pinned
+	recipe.ParentID = recipes[i].ParentID
+	recipe.UpdatedAt = Timestamp{time.Now()}
+	replaceRecipe(i, recipe)
+	recipesMu.Unlock()
+
+	auditor.record(c, "update", recipe.ID)
+	respondRecipe(c, http.StatusOK, recipe)
+}
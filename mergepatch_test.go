@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func mergePatchRequest(router http.Handler, id, patch string) *httptest.ResponseRecorder {
+	return serve(router, http.MethodPatch, "/recipe/"+id, patch, "Content-Type", mimeMergePatch)
+}
+
+func TestMergePatchReplacesRemovesAndKeeps(t *testing.T) {
+	r := testRecipe("r1", "Pancakes", "breakfast", "sweet")
+	r.Category = "breakfast"
+	r.Servings = 4
+	r.YieldText = "about 12"
+	r.Pinned = true
+	router := newTestRouter(t, nil, r)
+
+	w := mergePatchRequest(router, "r1", `{"name":"Fluffy pancakes","tags":["brunch"],"yieldText":null,"servings":null,"id":"other"}`)
+	expectStatus(t, w, http.StatusOK)
+	got := storedRecipe(t, "r1")
+
+	if got.Name != "Fluffy pancakes" || !slices.Equal(got.Tags, []string{"brunch"}) {
+		t.Errorf("name %q tags %q, want the patched values", got.Name, got.Tags)
+	}
+	if got.YieldText != "" || got.Servings != 0 {
+		t.Errorf("yieldText %q servings %d, want both removed", got.YieldText, got.Servings)
+	}
+	if got.Category != "breakfast" || !slices.Equal(got.Ingredients, r.Ingredients) || !slices.Equal(got.Instructions, r.Instructions) {
+		t.Errorf("got %+v, want category, ingredients and instructions untouched", got)
+	}
+	if got.ID != "r1" || !got.Pinned {
+		t.Errorf("id %q pinned %v, want server-managed fields kept", got.ID, got.Pinned)
+	}
+	if decodeBody[Recipe](t, w).Name != "Fluffy pancakes" {
+		t.Errorf("response %s does not show the patch", w.Body.String())
+	}
+}
+
+func TestMergePatchNestedObject(t *testing.T) {
+	r := testRecipe("r1", "Soup")
+	r.Translations = map[string]Translation{
+		"fr": {Name: "Soupe"},
+		"es": {Name: "Sopa"},
+	}
+	router := newTestRouter(t, nil, r)
+
+	expectStatus(t, mergePatchRequest(router, "r1", `{"translations":{"fr":{"name":"Potage"},"es":null}}`), http.StatusOK)
+	got := storedRecipe(t, "r1").Translations
+	if len(got) != 1 || got["fr"].Name != "Potage" {
+		t.Errorf("translations = %+v, want only fr, renamed", got)
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	expectStatus(t, serve(router, http.MethodPatch, "/recipe/r1", `{"name":"Stew"}`), http.StatusUnsupportedMediaType)
+	expectStatus(t, mergePatchRequest(router, "missing", `{"name":"Stew"}`), http.StatusNotFound)
+	expectStatus(t, mergePatchRequest(router, "r1", `["name"]`), http.StatusBadRequest)
+	expectStatus(t, mergePatchRequest(router, "r1", `{"servings":"four"}`), http.StatusBadRequest)
+	expectStatus(t, mergePatchRequest(router, "r1", `{"name":null}`), http.StatusUnprocessableEntity)
+	if got := storedRecipe(t, "r1").Name; got != "Soup" {
+		t.Errorf("failed patches changed the name to %q", got)
+	}
+}
+
+func TestMergePatchBodyLimit(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	w := mergePatchRequest(router, "r1", `{"name":"`+strings.Repeat("a", maxJSONBytes)+`"}`)
+	expectStatus(t, w, http.StatusRequestEntityTooLarge)
+	if got := storedRecipe(t, "r1").Name; got != "Soup" {
+		t.Errorf("oversized patch changed the name to %q", got)
+	}
+}
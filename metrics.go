@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBuckets are the Prometheus client default latency buckets, in
+// seconds.
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// parseBuckets parses a comma-separated list of strictly increasing,
+// positive bucket upper bounds in seconds.
+func parseBuckets(s string) ([]float64, error) {
+	parts := splitList(s)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no buckets given")
+	}
+	buckets := make([]float64, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("bucket %q is not a positive number", p)
+		}
+		if i > 0 && v <= buckets[i-1] {
+			return nil, fmt.Errorf("buckets must be strictly increasing, got %v after %v", v, buckets[i-1])
+		}
+		buckets[i] = v
+	}
+	return buckets, nil
+}
+
+// histogram is a minimal Prometheus-style histogram with labels.
+type histogram struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+// metricsRegistry lists every histogram exposed on /metrics.
+var metricsRegistry []*histogram
+
+func newHistogram(name, help string, buckets []float64, labels ...string) *histogram {
+	h := &histogram{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+	metricsRegistry = append(metricsRegistry, h)
+	return h
+}
+
+// observe records v for the series identified by labelValues, given in the
+// order of the histogram's labels.
+func (h *histogram) observe(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\x00")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.series[key]
+	if s == nil {
+		s = &histogramSeries{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *histogram) labelPairs(values []string, extra string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", h.labels[i], v))
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// write renders the histogram in the Prometheus text exposition format.
+func (h *histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	keys := make([]string, 0, len(h.series))
+	for k := range h.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.series[k]
+		for i, upper := range h.buckets {
+			le := fmt.Sprintf("le=%q", strconv.FormatFloat(upper, 'g', -1, 64))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.labelValues, le), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.labelValues, `le="+Inf"`), s.count)
+		fmt.Fprintf(w, "%s_sum%s %g\n", h.name, h.labelPairs(s.labelValues, ""), s.sum)
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(s.labelValues, ""), s.count)
+	}
+}
+
+// requestDuration starts with the default buckets; applyConfig swaps in
+// config.MetricsBuckets before anything is observed.
+var requestDuration = newHistogram(
+	"http_request_duration_seconds",
+	"Latency of HTTP requests by method, route and status.",
+	defaultBuckets,
+	"method", "route", "status",
+)
+
+// searchBuckets are finer than defaultBuckets, since an in-memory search
+// usually takes well under a millisecond.
+var searchBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+
+// searchDuration times the matching and paging of search results, without
+// binding or encoding, by match mode: single (?tag=), facet (?facet=),
+// expression (?q= tag expressions), multi (?tags=) and text (full-text
+// search).
+var searchDuration = newHistogram(
+	"search_duration_seconds",
+	"Time spent finding search results by match mode.",
+	searchBuckets,
+	"mode",
+)
+
+// MetricsMiddleware times every request into requestDuration, labelled with
+// the matched route pattern so IDs do not explode the label space.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		requestDuration.observe(time.Since(start).Seconds(), c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+	}
+}
+
+// MetricsHandler exposes all registered metrics for Prometheus to scrape.
+//
+// @Summary Prometheus metrics
+// @Tags ops
+// @Produce plain
+// @Success 200 {string} string
+// @Router /metrics [get]
+func MetricsHandler(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	for _, h := range metricsRegistry {
+		h.write(c.Writer)
+	}
+}
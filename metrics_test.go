@@ -0,0 +1,117 @@
+package main
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseBuckets(t *testing.T) {
+	got, err := parseBuckets(" 0.01, 0.1,1 ")
+	if err != nil || !slices.Equal(got, []float64{0.01, 0.1, 1}) {
+		t.Errorf("parseBuckets = %v, %v; want [0.01 0.1 1]", got, err)
+	}
+	for _, bad := range []string{",", "0.1,fast", "0.5,0.1", "0.1,0.1", "-1,1"} {
+		if _, err := parseBuckets(bad); err == nil {
+			t.Errorf("parseBuckets(%q) accepted", bad)
+		}
+	}
+}
+
+func TestMetricsBucketsFromEnv(t *testing.T) {
+	env := func(v string) func(string) string {
+		return func(k string) string {
+			if k == "METRICS_BUCKETS" {
+				return v
+			}
+			return ""
+		}
+	}
+	cfg, err := loadConfig(env("0.05,0.2,3"))
+	if err != nil || !slices.Equal(cfg.MetricsBuckets, []float64{0.05, 0.2, 3}) {
+		t.Errorf("METRICS_BUCKETS=0.05,0.2,3: buckets %v, err %v", cfg.MetricsBuckets, err)
+	}
+	cfg, err = loadConfig(env(""))
+	if err != nil || !slices.Equal(cfg.MetricsBuckets, defaultBuckets) {
+		t.Errorf("no METRICS_BUCKETS: buckets %v, err %v; want the defaults", cfg.MetricsBuckets, err)
+	}
+	if _, err := loadConfig(env("1,0.5")); err == nil || !strings.Contains(err.Error(), "METRICS_BUCKETS") {
+		t.Errorf("METRICS_BUCKETS=1,0.5: err %v, want one naming the variable", err)
+	}
+}
+
+func TestCustomBucketsAreExposed(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.MetricsBuckets = []float64{0.05, 0.2, 3}
+	})
+	expectStatus(t, serve(router, http.MethodGet, "/recipes", ""), http.StatusOK)
+
+	w := serve(router, http.MethodGet, "/metrics", "")
+	expectStatus(t, w, http.StatusOK)
+	prefix := `http_request_duration_seconds_bucket{method="GET",route="/recipes",status="200",le=`
+	var les []string
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			le, _, _ := strings.Cut(rest, "}")
+			les = append(les, le)
+		}
+	}
+	if want := []string{`"0.05"`, `"0.2"`, `"3"`, `"+Inf"`}; !slices.Equal(les, want) {
+		t.Errorf("buckets = %q, want %q", les, want)
+	}
+}
+
+// searchCounts returns the search_duration_seconds observation count for
+// each mode on the metrics endpoint.
+func searchCounts(t *testing.T, router http.Handler) map[string]string {
+	t.Helper()
+	w := serve(router, http.MethodGet, "/metrics", "")
+	expectStatus(t, w, http.StatusOK)
+	counts := make(map[string]string)
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		rest, ok := strings.CutPrefix(line, `search_duration_seconds_count{mode="`)
+		if !ok {
+			continue
+		}
+		mode, n, _ := strings.Cut(rest, `"} `)
+		counts[mode] = n
+	}
+	return counts
+}
+
+func TestSearchDurationByMode(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Tofu bowl", "vegan", "cuisine:thai"))
+	if got := searchCounts(t, router); len(got) != 0 {
+		t.Fatalf("search metrics before any search: %v", got)
+	}
+
+	for _, target := range []string{
+		"/recipes/search?tag=vegan",
+		"/recipes/search?facet=cuisine&value=thai",
+		"/recipes/search?q=" + url.QueryEscape("vegan AND NOT meat"),
+		"/recipes/search?tags=vegan,quick",
+		"/recipes/search/text?q=tofu",
+		"/recipes/search/text?q=bowl",
+	} {
+		expectStatus(t, serve(router, http.MethodGet, target, ""), http.StatusOK)
+	}
+	want := map[string]string{"single": "1", "facet": "1", "expression": "1", "multi": "1", "text": "2"}
+	if got := searchCounts(t, router); !maps.Equal(got, want) {
+		t.Errorf("search counts = %v, want %v", got, want)
+	}
+
+	body := `{"queries":[{"tag":"vegan"},{"text":"tofu"}]}`
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/multi-search", body), http.StatusOK)
+	want["single"], want["text"] = "2", "3"
+	if got := searchCounts(t, router); !maps.Equal(got, want) {
+		t.Errorf("after a multi-search, counts = %v, want %v", got, want)
+	}
+
+	w := serve(router, http.MethodGet, "/metrics", "")
+	if !strings.Contains(w.Body.String(), `search_duration_seconds_bucket{mode="text",le="0.0001"}`) {
+		t.Error("search histogram does not use the search buckets")
+	}
+}
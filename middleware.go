@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig controls the cross-origin headers sent by CORSMiddleware.
+type CORSConfig struct {
+	AllowOrigins  []string
+	AllowMethods  []string
+	AllowHeaders  []string
+	ExposeHeaders []string
+	// MaxAge is how long, in seconds, browsers may cache a preflight result.
+	MaxAge int
+}
+
+// splitList splits a comma-separated value, trimming blanks and dropping
+// empty entries.
+func splitList(s string) []string {
+	out := make([]string, 0)
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func (cfg CORSConfig) allowOrigin(origin string) string {
+	for _, o := range cfg.AllowOrigins {
+		if o == "*" {
+			return "*"
+		}
+		if strings.EqualFold(o, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// CORSMiddleware adds CORS headers to every response and answers preflight
+// requests directly with 204.
+func CORSMiddleware(cfg CORSConfig) gin.HandlerFunc {
+	methods := strings.Join(cfg.AllowMethods, ", ")
+	headers := strings.Join(cfg.AllowHeaders, ", ")
+	expose := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := strconv.Itoa(cfg.MaxAge)
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		allowed := cfg.allowOrigin(origin)
+		if allowed == "" {
+			c.Next()
+			return
+		}
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", allowed)
+		if allowed != "*" {
+			h.Add("Vary", "Origin")
+		}
+		if expose != "" {
+			h.Set("Access-Control-Expose-Headers", expose)
+		}
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", methods)
+			h.Set("Access-Control-Allow-Headers", headers)
+			h.Set("Access-Control-Max-Age", maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireContentType rejects requests whose Content-Type is missing or not
+// one of allowed with 415, before any handler tries to bind the body.
+func RequireContentType(allowed ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ct := c.ContentType()
+		if ct == "" {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type header is required; use one of " + strings.Join(allowed, ", ")})
+			return
+		}
+		for _, a := range allowed {
+			if strings.EqualFold(ct, a) {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "unsupported Content-Type " + ct + "; use one of " + strings.Join(allowed, ", ")})
+	}
+}
+
+// MaxQueryMiddleware rejects requests whose raw query string is longer than
+// limit bytes with 414, before anything parses it. A limit of zero or less
+// allows any length.
+func MaxQueryMiddleware(limit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit > 0 && len(c.Request.URL.RawQuery) > limit {
+			c.AbortWithStatusJSON(http.StatusRequestURITooLong, gin.H{"error": fmt.Sprintf("query string is longer than %d bytes", limit)})
+			return
+		}
+		c.Next()
+	}
+}
+
+// hstsHeader is sent on every response once HTTPS is enforced: one year,
+// covering subdomains.
+const hstsHeader = "max-age=31536000; includeSubDomains"
+
+// ForceHTTPSMiddleware redirects requests that a TLS-terminating proxy
+// reports as plain HTTP (X-Forwarded-Proto: http) to the same URL over
+// https with 308, which keeps the method and body, and sets HSTS.
+func ForceHTTPSMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Strict-Transport-Security", hstsHeader)
+		if strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "http") {
+			c.Redirect(http.StatusPermanentRedirect, "https://"+c.Request.Host+c.Request.URL.RequestURI())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SecurityHeaders maps response header names to the values
+// SecurityHeadersMiddleware sets on every response. By default these are
+// X-Content-Type-Options, X-Frame-Options and Referrer-Policy; there is no
+// default Content-Security-Policy, since a strict one breaks the Swagger UI.
+type SecurityHeaders map[string]string
+
+// securityHeaderVars maps each variable that overrides a security header to
+// the header it sets; the value "off" drops the header.
+var securityHeaderVars = map[string]string{
+	"SECURITY_NOSNIFF":         "X-Content-Type-Options",
+	"SECURITY_FRAME_OPTIONS":   "X-Frame-Options",
+	"SECURITY_CSP":             "Content-Security-Policy",
+	"SECURITY_REFERRER_POLICY": "Referrer-Policy",
+}
+
+// SecurityHeadersMiddleware sets headers on every response, including
+// errors, redirects and the Swagger UI pages.
+func SecurityHeadersMiddleware(headers SecurityHeaders) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		for name, v := range headers {
+			h.Set(name, v)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.CORS.AllowOrigins = []string{"https://app.example"}
+		cfg.CORS.MaxAge = 3600
+	})
+
+	w := serve(router, http.MethodOptions, "/recipes", "",
+		"Origin", "https://app.example",
+		"Access-Control-Request-Method", "POST")
+	expectStatus(t, w, http.StatusNoContent)
+	h := w.Header()
+	if got := h.Get("Access-Control-Max-Age"); got != "3600" {
+		t.Errorf("Access-Control-Max-Age = %q, want 3600", got)
+	}
+	if got := h.Get("Access-Control-Expose-Headers"); got != "X-Request-ID, ETag, Link" {
+		t.Errorf("Access-Control-Expose-Headers = %q, want X-Request-ID, ETag, Link", got)
+	}
+	if got := h.Get("Access-Control-Allow-Origin"); got != "https://app.example" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", got)
+	}
+}
+
+func TestCORSUnknownOrigin(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.CORS.AllowOrigins = []string{"https://app.example"}
+	})
+
+	w := serve(router, http.MethodGet, "/recipes", "", "Origin", "https://evil.example")
+	expectStatus(t, w, http.StatusOK)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+}
+
+func TestWriteEndpointsRequireContentType(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	body := `{"name":"Toast","ingredients":["bread"],"instructions":["Toast."]}`
+
+	for _, tc := range []struct{ method, target, contentType string }{
+		{http.MethodPost, "/recipes", ""},
+		{http.MethodPost, "/recipes", "text/plain"},
+		{http.MethodPut, "/recipe/r1", ""},
+		{http.MethodPut, "/recipe/r1", "text/plain; charset=utf-8"},
+		{http.MethodPatch, "/recipe/r1", "application/json"},
+		{http.MethodPatch, "/recipes/batch", "text/plain"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(body))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("%s %s with Content-Type %q: status %d, want 415", tc.method, tc.target, tc.contentType, w.Code)
+		}
+	}
+	if got := storedRecipe(t, "r1").Name; got != "Soup" {
+		t.Errorf("a rejected request changed the recipe to %q", got)
+	}
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", body, "Content-Type", "application/json; charset=utf-8"), http.StatusCreated)
+}
+
+func TestContentTypeAllowlist(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		cfg.WriteContentTypes = []string{"application/json", "text/plain"}
+	})
+	body := `{"name":"Toast","ingredients":["bread"],"instructions":["Toast."]}`
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", body, "Content-Type", "text/plain"), http.StatusCreated)
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", body, "Content-Type", "application/xml"), http.StatusUnsupportedMediaType)
+}
+
+func TestForceHTTPS(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) { cfg.ForceHTTPS = true })
+
+	req := httptest.NewRequest(http.MethodPost, "http://api.example.com/recipes?lint=true", strings.NewReader(newRecipeBody))
+	req.Header.Set("X-Forwarded-Proto", "http")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusPermanentRedirect)
+	if got := w.Header().Get("Location"); got != "https://api.example.com/recipes?lint=true" {
+		t.Errorf("Location = %q, want the https URL", got)
+	}
+	if got := w.Header().Get("Strict-Transport-Security"); got != hstsHeader {
+		t.Errorf("Strict-Transport-Security = %q, want %q", got, hstsHeader)
+	}
+
+	w = serve(router, http.MethodGet, "/recipes", "", "X-Forwarded-Proto", "https")
+	expectStatus(t, w, http.StatusOK)
+	if got := w.Header().Get("Strict-Transport-Security"); got != hstsHeader {
+		t.Errorf("HSTS over https = %q, want %q", got, hstsHeader)
+	}
+}
+
+func TestForceHTTPSOffByDefault(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodGet, "/recipes", "", "X-Forwarded-Proto", "http")
+	expectStatus(t, w, http.StatusOK)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Strict-Transport-Security = %q without FORCE_HTTPS", got)
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	want := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"Referrer-Policy":        "no-referrer",
+	}
+	for _, tc := range []struct {
+		target string
+		accept string
+	}{
+		{"/recipe/r1", ""},
+		{"/recipe/missing", ""},
+		{"/no/such/page", "text/html"},
+		{"/swagger/index.html", "text/html"},
+	} {
+		w := serve(router, http.MethodGet, tc.target, "", "Accept", tc.accept)
+		for name, v := range want {
+			if got := w.Header().Get(name); got != v {
+				t.Errorf("GET %s: %s = %q, want %q", tc.target, name, got, v)
+			}
+		}
+		if csp := w.Header().Get("Content-Security-Policy"); csp != "" {
+			t.Errorf("GET %s: Content-Security-Policy %q set by default", tc.target, csp)
+		}
+	}
+}
+
+func TestSecurityHeadersFromEnv(t *testing.T) {
+	env := map[string]string{
+		"SECURITY_CSP":           "default-src 'self'",
+		"SECURITY_FRAME_OPTIONS": "off",
+	}
+	cfg, err := loadConfig(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t, func(c *Config) { c.SecurityHeaders = cfg.SecurityHeaders })
+	w := serve(router, http.MethodGet, "/recipes", "")
+	if got := w.Header().Get("Content-Security-Policy"); got != "default-src 'self'" {
+		t.Errorf("Content-Security-Policy = %q, want default-src 'self'", got)
+	}
+	if got := w.Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("X-Frame-Options = %q, want it turned off", got)
+	}
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+
+	cfg, err = loadConfig(func(k string) string { return map[string]string{"SECURITY_HEADERS": "false"}[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.SecurityHeaders) != 0 {
+		t.Errorf("SECURITY_HEADERS=false left %v", cfg.SecurityHeaders)
+	}
+}
+
+func TestMaxQueryLength(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) { cfg.Server.MaxQueryBytes = 64 }, testRecipe("r1", "Soup", "vegan"))
+
+	atLimit := "/recipes/search?tag=vegan&x=" + strings.Repeat("a", 64-len("tag=vegan&x="))
+	expectStatus(t, serve(router, http.MethodGet, atLimit, ""), http.StatusOK)
+
+	w := serve(router, http.MethodGet, atLimit+"a", "")
+	expectStatus(t, w, http.StatusRequestURITooLong)
+	if got := decodeBody[ErrorResponse](t, w).Error; got != "query string is longer than 64 bytes" {
+		t.Errorf("error = %q", got)
+	}
+	long := "/recipes/search?" + strings.Repeat("tag=vegan&", 10)
+	expectStatus(t, serve(router, http.MethodGet, long, ""), http.StatusRequestURITooLong)
+}
+
+func TestMaxQueryLengthDefaultAndEnv(t *testing.T) {
+	router := newTestRouter(t, nil)
+	expectStatus(t, serve(router, http.MethodGet, "/recipes?q="+strings.Repeat("a", 8<<10), ""), http.StatusRequestURITooLong)
+	expectStatus(t, serve(router, http.MethodGet, "/recipes?q="+strings.Repeat("a", 1000), ""), http.StatusOK)
+
+	cfg, err := loadConfig(envMap(map[string]string{"MAX_QUERY_BYTES": "0"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router = newTestRouter(t, func(c *Config) { *c = cfg })
+	expectStatus(t, serve(router, http.MethodGet, "/recipes?q="+strings.Repeat("a", 16<<10), ""), http.StatusOK)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchQuery is one search of a multi-search. Exactly one of Tag, Q (a tag
+// expression), Tags, Facet or Text must be set; the other fields work like
+// the query parameters of the same names on the search endpoints.
+type SearchQuery struct {
+	Tag   string   `json:"tag,omitempty"`
+	Q     string   `json:"q,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Match string   `json:"match,omitempty" enums:"all,any"`
+	Facet string   `json:"facet,omitempty"`
+	Value string   `json:"value,omitempty"`
+	Text  string   `json:"text,omitempty"`
+	Field string   `json:"field,omitempty" enums:"name,tags,ingredients,instructions,all"`
+	Sort  string   `json:"sort,omitempty"`
+	Order string   `json:"order,omitempty"`
+	Page  int      `json:"page,omitempty"`
+	Limit int      `json:"limit,omitempty"`
+}
+
+// MultiSearchRequest is the body of POST /recipes/multi-search.
+type MultiSearchRequest struct {
+	Queries []SearchQuery `json:"queries" binding:"required"`
+}
+
+// plannedSearch is a validated SearchQuery, ready to run.
+type plannedSearch struct {
+	mode string
+	// run computes the result page. Callers must hold recipesMu.
+	run func() any
+}
+
+// plan validates q and prepares it to run, with the defaults of the search
+// endpoints for anything left out.
+func (q SearchQuery) plan() (plannedSearch, error) {
+	set := 0
+	for _, v := range []string{q.Tag, q.Q, strings.Join(q.Tags, ""), q.Facet, q.Text} {
+		if strings.TrimSpace(v) != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return plannedSearch{}, fmt.Errorf("exactly one of tag, q, tags, facet or text is required")
+	}
+	if q.Value != "" && strings.TrimSpace(q.Facet) == "" {
+		return plannedSearch{}, fmt.Errorf("value requires facet")
+	}
+	if q.Field != "" && strings.TrimSpace(q.Text) == "" {
+		return plannedSearch{}, fmt.Errorf("field requires text")
+	}
+	if q.Page < 0 || q.Limit < 0 {
+		return plannedSearch{}, fmt.Errorf("page and limit must not be negative")
+	}
+	page, limit := max(q.Page, 1), min(q.Limit, maxPageLimit)
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	spec, err := newSortSpec(q.Sort, q.Order)
+	if err != nil {
+		return plannedSearch{}, err
+	}
+
+	if tags := normalizeList(q.Tags); len(tags) > 0 {
+		var matchAny bool
+		switch q.Match {
+		case "", "all":
+		case "any":
+			matchAny = true
+		default:
+			return plannedSearch{}, fmt.Errorf("match must be all or any")
+		}
+		return plannedSearch{mode: "multi", run: func() any {
+			return paginateSearch(multiTagSearch(tags, matchAny, spec), page, limit)
+		}}, nil
+	}
+	if text := strings.TrimSpace(q.Text); text != "" {
+		fields, err := parseTextField(q.Field)
+		if err != nil {
+			return plannedSearch{}, err
+		}
+		return plannedSearch{mode: "text", run: func() any {
+			return textSearchPage(text, fields, spec, page, limit)
+		}}, nil
+	}
+	var expr tagExpr
+	mode := "single"
+	switch {
+	case strings.TrimSpace(q.Q) != "":
+		parsed, err := parseTagExpr(strings.TrimSpace(q.Q))
+		if err != nil {
+			return plannedSearch{}, fmt.Errorf("invalid q: %v", err)
+		}
+		expr, mode = parsed, "expression"
+	case strings.TrimSpace(q.Tag) != "":
+		expr = tagTerm(strings.TrimSpace(q.Tag))
+	default:
+		expr, mode = newFacetTerm(q.Facet, q.Value), "facet"
+	}
+	return plannedSearch{mode: mode, run: func() any {
+		return exprSearchPage(expr, spec, page, limit)
+	}}, nil
+}
+
+// MultiSearchHandler runs several independent searches in one request and
+// returns their result pages in the same order, all computed under one read
+// lock so they see the same state of the store. The whole request fails if
+// any query is invalid.
+//
+// @Summary Run several searches at once
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body MultiSearchRequest true "Searches to run"
+// @Success 200 {object} map[string][]PaginatedRecipes "Results of tags queries also carry matchCount"
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /recipes/multi-search [post]
+func MultiSearchHandler(c *gin.Context) {
+	var req MultiSearchRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Queries) > maxBatchIDs {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("at most %d queries per request", maxBatchIDs)})
+		return
+	}
+	plans := make([]plannedSearch, len(req.Queries))
+	for i, q := range req.Queries {
+		p, err := q.plan()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("query %d: %v", i, err)})
+			return
+		}
+		plans[i] = p
+	}
+
+	results := make([]any, len(plans))
+	recipesMu.RLock()
+	for i, p := range plans {
+		start := time.Now()
+		results[i] = p.run()
+		searchDuration.observe(time.Since(start).Seconds(), p.mode)
+	}
+	recipesMu.RUnlock()
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
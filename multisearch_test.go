@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMultiSearch(t *testing.T) {
+	soup := testRecipe("soup", "Tomato Soup", "vegan", "quick")
+	soup.Ingredients = []string{"4 tomatoes"}
+	router := newTestRouter(t, nil,
+		soup,
+		testRecipe("cake", "Chocolate Cake", "dessert"),
+		testRecipe("salad", "Green Salad", "vegan"),
+	)
+
+	w := serve(router, http.MethodPost, "/recipes/multi-search", `{"queries":[
+		{"tag":"dessert"},
+		{"tags":["vegan","quick"],"match":"any","sort":"name","order":"asc"},
+		{"text":"tomato","field":"ingredients"}
+	]}`)
+	expectStatus(t, w, http.StatusOK)
+	results := decodeBody[struct {
+		Results []PaginatedRecipes `json:"results"`
+	}](t, w).Results
+	want := [][]string{{"cake"}, {"soup", "salad"}, {"soup"}}
+	if len(results) != len(want) {
+		t.Fatalf("got %d result sets, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		ids := make([]string, 0)
+		for _, recipe := range r.Data {
+			ids = append(ids, recipe.ID)
+		}
+		if !slices.Equal(ids, want[i]) {
+			t.Errorf("query %d = %q, want %q", i, ids, want[i])
+		}
+	}
+}
+
+func TestMultiSearchRejectsInvalidQueries(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup", "vegan"))
+	for _, tc := range []struct {
+		body, wantErr string
+	}{
+		{`{"queries":[{"tag":"vegan"},{}]}`, "query 1:"},
+		{`{"queries":[{"tag":"vegan","text":"soup"}]}`, "exactly one of"},
+		{`{"queries":[{"tags":["vegan"],"match":"some"}]}`, "match must be"},
+		{`{"queries":[{"tag":"vegan","sort":"cost"}]}`, "sort must be"},
+		{`{"queries":[{"q":"vegan AND"}]}`, "invalid q"},
+	} {
+		w := serve(router, http.MethodPost, "/recipes/multi-search", tc.body)
+		expectStatus(t, w, http.StatusBadRequest)
+		if got := decodeBody[ErrorResponse](t, w).Error; !strings.Contains(got, tc.wantErr) {
+			t.Errorf("%s: error = %q, want it to contain %q", tc.body, got, tc.wantErr)
+		}
+	}
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/multi-search", `{}`), http.StatusUnprocessableEntity)
+}
+
+func TestMultiSearchBodyLimit(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup", "vegan"))
+	body := `{"queries":[{"tag":"vegan","value":"` + strings.Repeat("a", maxJSONBytes) + `"}]}`
+	expectStatus(t, serve(router, http.MethodPost, "/recipes/multi-search", body), http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NormalizeResponse reports the recipes changed by a normalization pass.
+type NormalizeResponse struct {
+	Changed int      `json:"changed"`
+	IDs     []string `json:"ids"`
+}
+
+// trimLines trims each entry of lines and drops the blank ones.
+func trimLines(lines []string) []string {
+	if lines == nil {
+		return nil
+	}
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// cleanupRecipe applies normalizeRecipe plus the fixes only imported data
+// needs: trimmed name and yield, no blank ingredients, instructions or
+// steps, and UTC timestamps. It works on copies of r's slices, so the
+// stored recipe is untouched until the result is saved.
+func cleanupRecipe(r Recipe) Recipe {
+	r.Steps = slices.Clone(r.Steps)
+	r.Videos = slices.Clone(r.Videos)
+	normalizeRecipe(&r)
+	r.Name = strings.TrimSpace(r.Name)
+	r.YieldText = strings.TrimSpace(r.YieldText)
+	r.Ingredients = trimLines(r.Ingredients)
+	r.Instructions = trimLines(r.Instructions)
+	r.Steps = slices.DeleteFunc(r.Steps, func(s Step) bool { return s.Text == "" })
+	r.PublishedAt = utcTime(r.PublishedAt)
+	r.UpdatedAt = utcTime(r.UpdatedAt)
+	return r
+}
+
+// utcTime converts t to UTC if it has a non-zero offset, leaving times that
+// already serialize as UTC untouched.
+func utcTime(t Timestamp) Timestamp {
+	if _, offset := t.Zone(); offset != 0 {
+		return Timestamp{t.UTC()}
+	}
+	return t
+}
+
+// normalizeAll cleans up every recipe and gives a fresh ID to any recipe
+// without one or sharing one with an earlier recipe. Changed recipes get a
+// new UpdatedAt so sync clients fetch them again. It returns the cleaned
+// list and the IDs of the changed recipes. Callers must hold recipesMu.
+func normalizeAll(now time.Time) ([]Recipe, []string) {
+	list := make([]Recipe, len(recipes))
+	seen := make(map[string]bool, len(recipes))
+	ids := []string{}
+	for i, r := range recipes {
+		n := cleanupRecipe(r)
+		if n.ID == "" || seen[n.ID] {
+			n.ID = newRecipeID()
+		}
+		seen[n.ID] = true
+		if !reflect.DeepEqual(n, r) {
+			n.UpdatedAt = Timestamp{now}
+			ids = append(ids, n.ID)
+		}
+		list[i] = n
+	}
+	return list, ids
+}
+
+// NormalizeHandler cleans up the whole collection in one pass, applying the
+// same rules as create and update plus the fixes imported data needs, and
+// reports which recipes changed.
+//
+// @Summary Normalize every recipe
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} NormalizeResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /admin/normalize [post]
+func NormalizeHandler(c *gin.Context) {
+	recipesMu.Lock()
+	list, ids := normalizeAll(time.Now())
+	if len(ids) > 0 {
+		replaceAllRecipes(list)
+	}
+	recipesMu.Unlock()
+
+	for _, id := range ids {
+		auditor.record(c, "update", id)
+	}
+	c.JSON(http.StatusOK, NormalizeResponse{Changed: len(ids), IDs: ids})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNormalizeCollection(t *testing.T) {
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	messy := Recipe{
+		ID:           "messy",
+		Name:         "  Carrot soup  ",
+		Tags:         []string{"Vegan", "vegan ", " Quick", ""},
+		Ingredients:  []string{"  2 carrots ", "", "salt"},
+		Instructions: []string{"Boil. ", "   "},
+		Steps:        []Step{{Text: "  "}, {Text: " Boil. ", DurationSeconds: 600}},
+		PublishedAt:  Timestamp{published},
+		UpdatedAt:    Timestamp{published},
+	}
+	noID := testRecipe("", "Stew")
+	dup := testRecipe("clean", "Copy of clean")
+	router := newTestRouter(t, withUsers, messy, testRecipe("clean", "Bread"), noID, dup)
+	auth := []string{"X-API-KEY", "alice-key"}
+
+	expectStatus(t, serve(router, http.MethodPost, "/admin/normalize", ""), http.StatusUnauthorized)
+
+	w := serve(router, http.MethodPost, "/admin/normalize", "", auth...)
+	expectStatus(t, w, http.StatusOK)
+	resp := decodeBody[NormalizeResponse](t, w)
+	if resp.Changed != 3 || len(resp.IDs) != 3 || resp.IDs[0] != "messy" {
+		t.Fatalf("got %+v, want messy and the two ID fixes changed", resp)
+	}
+
+	got := storedRecipe(t, "messy")
+	if got.Name != "Carrot soup" {
+		t.Errorf("name = %q", got.Name)
+	}
+	if want := []string{"vegan", "quick"}; !slices.Equal(got.Tags, want) {
+		t.Errorf("tags = %q, want %q", got.Tags, want)
+	}
+	if want := []string{"2 carrots", "salt"}; !slices.Equal(got.Ingredients, want) {
+		t.Errorf("ingredients = %q, want %q", got.Ingredients, want)
+	}
+	if want := []string{"Boil."}; !slices.Equal(got.Instructions, want) {
+		t.Errorf("instructions = %q, want %q", got.Instructions, want)
+	}
+	if want := []Step{{Text: "Boil.", DurationSeconds: 600}}; !slices.Equal(got.Steps, want) {
+		t.Errorf("steps = %+v, want %+v", got.Steps, want)
+	}
+	if got.PublishedAt.Location() != time.UTC || !got.PublishedAt.Equal(published) {
+		t.Errorf("publishedAt = %v, want %v in UTC", got.PublishedAt, published)
+	}
+	if !got.UpdatedAt.After(published) {
+		t.Errorf("updatedAt = %v, want it bumped", got.UpdatedAt)
+	}
+
+	if clean := storedRecipe(t, "clean"); clean.Name != "Bread" || !clean.UpdatedAt.IsZero() {
+		t.Errorf("clean recipe changed to %+v", clean)
+	}
+	for _, id := range resp.IDs[1:] {
+		if id == "" || id == "clean" || !xidScheme.valid(id) {
+			t.Errorf("changed ID %q, want a fresh xid", id)
+		}
+		storedRecipe(t, id)
+	}
+
+	w = serve(router, http.MethodPost, "/admin/normalize", "", auth...)
+	expectStatus(t, w, http.StatusOK)
+	if again := decodeBody[NormalizeResponse](t, w); again.Changed != 0 {
+		t.Errorf("second pass changed %+v, want nothing", again)
+	}
+}
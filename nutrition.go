@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Nutrition is an amount of energy and macronutrients.
+type Nutrition struct {
+	Calories float64 `json:"calories"`
+	ProteinG float64 `json:"proteinG"`
+	FatG     float64 `json:"fatG"`
+	CarbsG   float64 `json:"carbsG"`
+}
+
+func (n Nutrition) add(o Nutrition) Nutrition {
+	return Nutrition{n.Calories + o.Calories, n.ProteinG + o.ProteinG, n.FatG + o.FatG, n.CarbsG + o.CarbsG}
+}
+
+func (n Nutrition) scale(factor float64) Nutrition {
+	return Nutrition{n.Calories * factor, n.ProteinG * factor, n.FatG * factor, n.CarbsG * factor}
+}
+
+// rounded rounds every value to one decimal for display.
+func (n Nutrition) rounded() Nutrition {
+	r := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return Nutrition{r(n.Calories), r(n.ProteinG), r(n.FatG), r(n.CarbsG)}
+}
+
+// foodEntry is the nutrition of one food in the table, for the amount in
+// per, such as "100 g" or "1" for one item.
+type foodEntry struct {
+	Nutrition
+	qty  float64
+	unit string
+}
+
+// nutritionTable maps a food, as its words from ingredientWords such as
+// "olive oil", to its nutrition. It is nil when no table is configured.
+var nutritionTable map[string]foodEntry
+
+// loadNutritionTable reads the food table named by NUTRITION_TABLE, if any:
+// a JSON object mapping a food to its nutrition for an amount, e.g.
+//
+//	{"flour": {"per": "100 g", "calories": 364, "proteinG": 10.3,
+//	           "fatG": 1, "carbsG": 76.3}}
+func loadNutritionTable(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var raw map[string]struct {
+		Per string `json:"per"`
+		Nutrition
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	table := make(map[string]foodEntry, len(raw))
+	for food, e := range raw {
+		key := strings.Join(ingredientWords(food), " ")
+		if key == "" {
+			return fmt.Errorf("%s: %q has no words to match", path, food)
+		}
+		qty, unit, rest, ok := parseIngredient(e.Per)
+		if !ok || qty <= 0 || rest != "" {
+			return fmt.Errorf("%s: %q: per must be an amount such as \"100 g\" or \"1\", got %q", path, food, e.Per)
+		}
+		table[key] = foodEntry{Nutrition: e.Nutrition, qty: qty, unit: unit}
+	}
+	if len(table) == 0 {
+		return fmt.Errorf("nutrition table %s is empty", path)
+	}
+	nutritionTable = table
+	return nil
+}
+
+// unitMeasures gives each canonical mass or volume unit of kitchenUnits its
+// size in grams or millilitres, so an ingredient can be compared with a
+// table entry given in another unit of the same kind. Cups and spoons are
+// metric.
+var unitMeasures = map[string]struct {
+	kind string
+	size float64
+}{
+	"g":    {"mass", 1},
+	"kg":   {"mass", 1000},
+	"oz":   {"mass", 28.3495},
+	"lb":   {"mass", 453.592},
+	"ml":   {"volume", 1},
+	"l":    {"volume", 1000},
+	"cup":  {"volume", 240},
+	"tbsp": {"volume", 15},
+	"tsp":  {"volume", 5},
+}
+
+// portion returns how many of e's amounts qty of unit is. ok is false when
+// the units cannot be compared, such as grams against cups or a bare count.
+func (e foodEntry) portion(qty float64, unit string) (float64, bool) {
+	if unit == e.unit {
+		return qty / e.qty, true
+	}
+	from, ok1 := unitMeasures[unit]
+	to, ok2 := unitMeasures[e.unit]
+	if !ok1 || !ok2 || from.kind != to.kind {
+		return 0, false
+	}
+	return qty * from.size / (e.qty * to.size), true
+}
+
+// lookupFood finds the table entry for an ingredient name: the longest food
+// whose words appear in it, ignoring case, accents and plurals, so
+// "plain flour, sifted" matches "flour" and "eggs" matches "egg". Foods of
+// the same length are decided alphabetically so the choice does not depend
+// on map order.
+func lookupFood(name string) (string, foodEntry, bool) {
+	words := " " + strings.Join(ingredientWords(name), " ") + " "
+	best := ""
+	for food := range nutritionTable {
+		if !strings.Contains(words, " "+food+" ") {
+			continue
+		}
+		if len(food) > len(best) || (len(food) == len(best) && food < best) {
+			best = food
+		}
+	}
+	e, ok := nutritionTable[best]
+	return best, e, ok
+}
+
+// IngredientNutrition is the estimate for one ingredient line and the
+// table food it was matched to.
+type IngredientNutrition struct {
+	Ingredient string    `json:"ingredient"`
+	Food       string    `json:"food"`
+	Nutrition  Nutrition `json:"nutrition"`
+}
+
+// NutritionEstimate is the estimated nutrition of a whole recipe and, when
+// it has numeric servings, of one serving. Unknown lists the ingredient
+// lines left out of the totals.
+type NutritionEstimate struct {
+	ID          string                `json:"id"`
+	Servings    int                   `json:"servings,omitempty"`
+	Total       Nutrition             `json:"total"`
+	PerServing  *Nutrition            `json:"perServing,omitempty"`
+	Ingredients []IngredientNutrition `json:"ingredients"`
+	Unknown     []string              `json:"unknown"`
+}
+
+// estimateNutrition sums the nutrition of r's ingredients from the table.
+// A line counts as unknown when its food is not in the table, it has no
+// leading quantity, or its unit cannot be compared with the table's.
+func estimateNutrition(r Recipe) NutritionEstimate {
+	est := NutritionEstimate{ID: r.ID, Ingredients: make([]IngredientNutrition, 0), Unknown: make([]string, 0)}
+	var total Nutrition
+	for _, line := range r.Ingredients {
+		qty, unit, name, ok := parseIngredient(line)
+		food, entry, found := lookupFood(name)
+		if !ok || !found {
+			est.Unknown = append(est.Unknown, line)
+			continue
+		}
+		portion, ok := entry.portion(qty, unit)
+		if !ok {
+			est.Unknown = append(est.Unknown, line)
+			continue
+		}
+		n := entry.Nutrition.scale(portion)
+		total = total.add(n)
+		est.Ingredients = append(est.Ingredients, IngredientNutrition{Ingredient: line, Food: food, Nutrition: n.rounded()})
+	}
+	est.Total = total.rounded()
+	if r.Servings > 0 {
+		per := total.scale(1 / float64(r.Servings)).rounded()
+		est.Servings = r.Servings
+		est.PerServing = &per
+	}
+	return est
+}
+
+// NutritionEstimateHandler estimates a recipe's nutrition by looking up each
+// ingredient in the food table loaded from NUTRITION_TABLE and scaling it by
+// the ingredient's quantity. It is an estimate: lines the table cannot
+// account for are listed as unknown and left out.
+//
+// @Summary Estimate a recipe's nutrition
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} NutritionEstimate
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse "No food table configured"
+// @Router /recipe/{id}/nutrition/estimate [get]
+func NutritionEstimateHandler(c *gin.Context) {
+	if nutritionTable == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "nutrition estimates need a food table; set NUTRITION_TABLE"})
+		return
+	}
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := recipes[i]
+	recipesMu.RUnlock()
+
+	c.JSON(http.StatusOK, estimateNutrition(recipe))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const nutritionFixture = `{
+	"flour": {"per": "100 g", "calories": 364, "proteinG": 10.3, "fatG": 1, "carbsG": 76.3},
+	"Eggs": {"per": "1", "calories": 72, "proteinG": 6.3, "fatG": 4.8, "carbsG": 0.4},
+	"milk": {"per": "1 cup", "calories": 150, "proteinG": 8, "fatG": 8, "carbsG": 12},
+	"oil": {"per": "1 tbsp", "calories": 120, "fatG": 14},
+	"olive oil": {"per": "1 tbsp", "calories": 119, "fatG": 13.5}
+}`
+
+// nutritionRouter is a test router with nutritionFixture loaded.
+func nutritionRouter(t *testing.T, seed ...Recipe) http.Handler {
+	t.Helper()
+	router := newTestRouter(t, nil, seed...)
+	if err := loadNutritionTable(writeFixture(t, nutritionFixture)); err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+func TestNutritionEstimate(t *testing.T) {
+	r := testRecipe("r1", "Pancakes")
+	r.Servings = 2
+	r.Ingredients = []string{"200 g plain flour", "2 eggs", "120 ml milk", "1 tbsp olive oil", "1 cup flour", "salt to taste", "2 tbsp sugar"}
+	router := nutritionRouter(t, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/nutrition/estimate", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[NutritionEstimate](t, w)
+
+	if want := (Nutrition{Calories: 1066, ProteinG: 37.2, FatG: 29.1, CarbsG: 159.4}); got.Total != want {
+		t.Errorf("total = %+v, want %+v", got.Total, want)
+	}
+	if want := (Nutrition{Calories: 533, ProteinG: 18.6, FatG: 14.6, CarbsG: 79.7}); got.Servings != 2 || got.PerServing == nil || *got.PerServing != want {
+		t.Errorf("per serving = %+v for %d servings, want %+v for 2", got.PerServing, got.Servings, want)
+	}
+	var foods []string
+	for _, ing := range got.Ingredients {
+		foods = append(foods, ing.Food)
+	}
+	if want := []string{"flour", "egg", "milk", "olive oil"}; !slices.Equal(foods, want) {
+		t.Errorf("matched foods = %q, want %q", foods, want)
+	}
+	if want := []string{"1 cup flour", "salt to taste", "2 tbsp sugar"}; !slices.Equal(got.Unknown, want) {
+		t.Errorf("unknown = %q, want %q", got.Unknown, want)
+	}
+}
+
+func TestNutritionEstimateWithoutServings(t *testing.T) {
+	r := testRecipe("r1", "Omelette")
+	r.Ingredients = []string{"3 eggs"}
+	router := nutritionRouter(t, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/nutrition/estimate", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[NutritionEstimate](t, w)
+	if got.Total.Calories != 216 || got.PerServing != nil || len(got.Unknown) != 0 {
+		t.Errorf("got %+v, want 216 calories in total and nothing per serving", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/nutrition/estimate", ""), http.StatusNotFound)
+}
+
+func TestNutritionEstimateNeedsTable(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/r1/nutrition/estimate", ""), http.StatusServiceUnavailable)
+}
+
+func TestLoadNutritionTableErrors(t *testing.T) {
+	newTestRouter(t, nil)
+	for _, tc := range []struct{ data, wantErr string }{
+		{`{}`, "is empty"},
+		{`{"flour": {"per": "some", "calories": 1}}`, "per must be an amount"},
+		{`{"flour": {"per": "0 g", "calories": 1}}`, "per must be an amount"},
+		{`{"!!": {"per": "1", "calories": 1}}`, "has no words"},
+		{`[1, 2]`, "cannot unmarshal"},
+	} {
+		err := loadNutritionTable(writeFixture(t, tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: error = %v, want it to contain %q", tc.data, err, tc.wantErr)
+		}
+	}
+	if nutritionTable != nil {
+		t.Error("a failed load replaced the table")
+	}
+}
+
+func TestLookupFoodBreaksTiesAlphabetically(t *testing.T) {
+	newTestRouter(t, nil)
+	nutritionTable = map[string]foodEntry{
+		"hot chili": {qty: 1},
+		"chili oil": {qty: 1},
+		"oil":       {qty: 1},
+	}
+	for range 20 {
+		if food, _, _ := lookupFood("hot chili oil"); food != "chili oil" {
+			t.Fatalf("lookupFood chose %q, want chili oil", food)
+		}
+	}
+}
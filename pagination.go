@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Pagination describes where a page sits within the full result set.
+type Pagination struct {
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"totalPages"`
+	HasNext    bool `json:"hasNext"`
+	HasPrev    bool `json:"hasPrev"`
+}
+
+// Page is the response envelope of the list and search endpoints. Search
+// results cut off at config.SearchMaxResults set Truncated, with Total counting
+// every match; Pagination then covers only the results kept.
+type Page[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+	Truncated  bool       `json:"truncated,omitempty"`
+	Total      int        `json:"total,omitempty"`
+}
+
+// PaginatedRecipes is a page of plain recipes.
+type PaginatedRecipes = Page[Recipe]
+
+// parsePagination reads ?page= and ?limit=. Missing values default to the
+// first page and defaultPageLimit, and limits above maxPageLimit are clamped.
+// Non-numeric, zero or negative values are rejected with a message suitable
+// for a 400 response.
+func parsePagination(c *gin.Context) (page, limit int, err error) {
+	page, err = positiveQueryInt(c, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err = positiveQueryInt(c, "limit", defaultPageLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	return page, min(limit, maxPageLimit), nil
+}
+
+// positiveQueryInt parses the query parameter key as an integer greater than
+// zero, returning def when it is absent.
+func positiveQueryInt(c *gin.Context, key string, def int) (int, error) {
+	v, ok := c.GetQuery(key)
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a whole number, got %q", key, v)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%s must be greater than zero, got %d", key, n)
+	}
+	return n, nil
+}
+
+// paginate returns the requested page of list with its navigation metadata.
+// A page past the end yields empty data.
+func paginate[T any](list []T, page, limit int) Page[T] {
+	p := Pagination{Page: page, Limit: limit, Total: len(list)}
+	if limit > 0 {
+		p.TotalPages = (p.Total + limit - 1) / limit
+	}
+	p.HasNext = page < p.TotalPages
+	p.HasPrev = page > 1
+
+	data := make([]T, 0)
+	if limit > 0 && page > 0 {
+		start := (page - 1) * limit
+		if start < len(list) {
+			data = list[start:min(start+limit, len(list))]
+		}
+	}
+	return Page[T]{Data: data, Pagination: p}
+}
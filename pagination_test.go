@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParsePagination(t *testing.T) {
+	for query, want := range map[string][2]int{
+		"":                 {1, defaultPageLimit},
+		"page=3":           {3, defaultPageLimit},
+		"page=2&limit=5":   {2, 5},
+		"limit=%2050%20":   {1, 50},
+		"limit=1000":       {1, maxPageLimit},
+		"page=1&limit=100": {1, 100},
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		page, limit, err := parsePagination(c)
+		if err != nil || page != want[0] || limit != want[1] {
+			t.Errorf("%q: got page %d, limit %d, err %v; want %d, %d", query, page, limit, err, want[0], want[1])
+		}
+	}
+	for query, want := range map[string]string{
+		"page=0":    "page must be greater than zero, got 0",
+		"page=-3":   "page must be greater than zero, got -3",
+		"page=":     `page must be a whole number, got ""`,
+		"limit=abc": `limit must be a whole number, got "abc"`,
+		"limit=0":   "limit must be greater than zero, got 0",
+		"limit=2.5": `limit must be a whole number, got "2.5"`,
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		if _, _, err := parsePagination(c); err == nil || err.Error() != want {
+			t.Errorf("%q: error %v, want %q", query, err, want)
+		}
+	}
+}
+
+func TestPaginationErrorsOnEveryEndpoint(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup", "quick"))
+	for _, target := range []string{
+		"/recipes?page=0",
+		"/recipes/search?tag=quick&limit=abc",
+		"/recipes/search/text?q=soup&page=-3",
+		"/recipes/search?tags=quick,vegan&page=0",
+	} {
+		w := serve(router, http.MethodGet, target, "")
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	list := []int{1, 2, 3, 4, 5, 6, 7}
+	for _, tc := range []struct {
+		page, limit int
+		data        []int
+		want        Pagination
+	}{
+		{1, 3, []int{1, 2, 3}, Pagination{Page: 1, Limit: 3, Total: 7, TotalPages: 3, HasNext: true}},
+		{2, 3, []int{4, 5, 6}, Pagination{Page: 2, Limit: 3, Total: 7, TotalPages: 3, HasNext: true, HasPrev: true}},
+		{3, 3, []int{7}, Pagination{Page: 3, Limit: 3, Total: 7, TotalPages: 3, HasPrev: true}},
+		{4, 3, []int{}, Pagination{Page: 4, Limit: 3, Total: 7, TotalPages: 3, HasPrev: true}},
+		{1, 7, []int{1, 2, 3, 4, 5, 6, 7}, Pagination{Page: 1, Limit: 7, Total: 7, TotalPages: 1}},
+		{1, 0, []int{}, Pagination{Page: 1, Limit: 0, Total: 7}},
+	} {
+		got := paginate(list, tc.page, tc.limit)
+		if !slices.Equal(got.Data, tc.data) || got.Pagination != tc.want {
+			t.Errorf("paginate(page %d, limit %d) = %v %+v, want %v %+v", tc.page, tc.limit, got.Data, got.Pagination, tc.data, tc.want)
+		}
+	}
+}
+
+func TestListPaginationMetadata(t *testing.T) {
+	seed := make([]Recipe, 5)
+	for i := range seed {
+		seed[i] = testRecipe(fmt.Sprint(i), fmt.Sprint(i))
+	}
+	router := newTestRouter(t, nil, seed...)
+
+	w := serve(router, http.MethodGet, "/recipes?sort=name&order=asc&page=2&limit=2", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[PaginatedRecipes](t, w)
+	want := Pagination{Page: 2, Limit: 2, Total: 5, TotalPages: 3, HasNext: true, HasPrev: true}
+	if got.Pagination != want {
+		t.Errorf("pagination = %+v, want %+v", got.Pagination, want)
+	}
+	if len(got.Data) != 2 || got.Data[0].ID != "2" || got.Data[1].ID != "3" {
+		t.Errorf("data = %+v, want recipes 2 and 3", got.Data)
+	}
+}
+
+// allPages walks every page of target, limit recipes at a time, and returns
+// the IDs in order.
+func allPages(t *testing.T, router http.Handler, target string, limit int) []string {
+	t.Helper()
+	var ids []string
+	for page := 1; ; page++ {
+		w := serve(router, http.MethodGet, fmt.Sprintf("%s&page=%d&limit=%d", target, page, limit), "")
+		expectStatus(t, w, http.StatusOK)
+		got := decodeBody[PaginatedRecipes](t, w)
+		for _, r := range got.Data {
+			ids = append(ids, r.ID)
+		}
+		if !got.Pagination.HasNext {
+			return ids
+		}
+	}
+}
+
+func TestDeleteKeepsPaginationStable(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := make([]Recipe, 10)
+	for i := range seed {
+		seed[i] = testRecipe(fmt.Sprintf("r%d", i), fmt.Sprintf("Recipe %d", i%4))
+		// Pairs share a timestamp so ties must be broken deterministically.
+		seed[i].PublishedAt = Timestamp{base.Add(time.Duration(i/2) * time.Hour)}
+		seed[i].UpdatedAt = seed[i].PublishedAt
+	}
+
+	for _, target := range []string{"/recipes?sort=publishedAt", "/recipes?sort=name&order=asc"} {
+		router := newTestRouter(t, nil, seed...)
+		before := allPages(t, router, target, 3)
+		if len(before) != 10 {
+			t.Fatalf("%s: listed %q, want 10 recipes", target, before)
+		}
+		if again := allPages(t, router, target, 3); !slices.Equal(again, before) {
+			t.Fatalf("%s: order changed between walks: %q then %q", target, before, again)
+		}
+
+		deleted := before[4]
+		expectStatus(t, serve(router, http.MethodDelete, "/recipe/"+deleted, ""), http.StatusOK)
+		after := allPages(t, router, target, 3)
+		want := slices.Delete(slices.Clone(before), 4, 5)
+		if !slices.Equal(after, want) {
+			t.Errorf("%s: after deleting %s got %q, want %q", target, deleted, after, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecipePatch is a partial update: only non-nil fields are applied.
+type RecipePatch struct {
+	Name         *string   `json:"name"`
+	Tags         *[]string `json:"tags"`
+	Category     *string   `json:"category"`
+	Ingredients  *[]string `json:"ingredients"`
+	Instructions *[]string `json:"instructions"`
+	Steps        *[]Step   `json:"steps"`
+	Allergens    *[]string `json:"allergens"`
+	Equipment    *[]string `json:"equipment"`
+	Videos       *[]Video  `json:"videos"`
+	Difficulty   *string   `json:"difficulty"`
+	PrepTime     *int      `json:"prepTime"`
+	CookTime     *int      `json:"cookTime"`
+	Servings     *int      `json:"servings"`
+	YieldText    *string   `json:"yieldText"`
+	CostCents    *int      `json:"costCents"`
+	Currency     *string   `json:"currency"`
+}
+
+// apply copies every set field of p onto r.
+func (p RecipePatch) apply(r *Recipe) {
+	if p.Name != nil {
+		r.Name = *p.Name
+	}
+	if p.Tags != nil {
+		r.Tags = *p.Tags
+	}
+	if p.Category != nil {
+		r.Category = *p.Category
+	}
+	if p.Ingredients != nil {
+		r.Ingredients = *p.Ingredients
+	}
+	if p.Instructions != nil {
+		r.Instructions = *p.Instructions
+	}
+	if p.Steps != nil {
+		r.Steps = *p.Steps
+		if p.Instructions == nil {
+			// Let normalizeRecipe derive instructions from the new steps.
+			r.Instructions = nil
+		}
+	}
+	if p.Allergens != nil {
+		r.Allergens = *p.Allergens
+	}
+	if p.Equipment != nil {
+		r.Equipment = *p.Equipment
+	}
+	if p.Videos != nil {
+		r.Videos = *p.Videos
+	}
+	if p.Difficulty != nil {
+		r.Difficulty = *p.Difficulty
+	}
+	if p.PrepTime != nil {
+		r.PrepTime = *p.PrepTime
+	}
+	if p.CookTime != nil {
+		r.CookTime = *p.CookTime
+	}
+	if p.Servings != nil {
+		r.Servings = *p.Servings
+	}
+	if p.YieldText != nil {
+		r.YieldText = *p.YieldText
+	}
+	if p.CostCents != nil {
+		r.CostCents = *p.CostCents
+	}
+	if p.Currency != nil {
+		r.Currency = *p.Currency
+	}
+}
+
+// BatchPatchRequest is the body of PATCH /recipes/batch.
+type BatchPatchRequest struct {
+	IDs   []string    `json:"ids" binding:"required"`
+	Patch RecipePatch `json:"patch"`
+}
+
+// BatchItemResult reports the outcome for one ID of a batch operation.
+type BatchItemResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// BatchPatchRecipesHandler applies the same partial update to every listed
+// recipe. The patch is validated against all targets before any of them is
+// changed, so the batch either applies everywhere it can or nowhere.
+//
+// @Summary Patch several recipes
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param request body BatchPatchRequest true "IDs and the partial update to apply"
+// @Success 200 {object} map[string][]BatchItemResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /recipes/batch [patch]
+func BatchPatchRecipesHandler(c *gin.Context) {
+	var req BatchPatchRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.IDs) > maxBatchIDs {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("at most %d ids per request", maxBatchIDs)})
+		return
+	}
+
+	recipesMu.Lock()
+	defer recipesMu.Unlock()
+
+	results := make([]BatchItemResult, len(req.IDs))
+	patched := make(map[int]Recipe, len(req.IDs))
+	now := time.Now()
+	for n, id := range req.IDs {
+		i := findRecipe(id)
+		if i < 0 {
+			results[n] = BatchItemResult{ID: id, Status: "notFound"}
+			continue
+		}
+		recipe := recipes[i]
+		req.Patch.apply(&recipe)
+		normalizeRecipe(&recipe)
+		if err := validateRecipe(&recipe); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("recipe %s: %v", id, err)})
+			return
+		}
+		recipe.UpdatedAt = Timestamp{now}
+		patched[i] = recipe
+		results[n] = BatchItemResult{ID: id, Status: "updated"}
+	}
+	for i, recipe := range patched {
+		replaceRecipe(i, recipe)
+		auditor.record(c, "update", recipe.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
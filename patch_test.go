@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestBatchPatchCategory(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("a", "A"), testRecipe("b", "B"), testRecipe("c", "C"))
+
+	w := serve(router, http.MethodPatch, "/recipes/batch", `{"ids":["a","missing","c"],"patch":{"category":"Dessert"}}`)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[map[string][]BatchItemResult](t, w)["results"]
+	want := []BatchItemResult{{"a", "updated"}, {"missing", "notFound"}, {"c", "updated"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("results = %+v, want %+v", got, want)
+	}
+	for id, category := range map[string]string{"a": "dessert", "b": "", "c": "dessert"} {
+		if got := storedRecipe(t, id).Category; got != category {
+			t.Errorf("recipe %s category = %q, want %q", id, got, category)
+		}
+	}
+}
+
+func TestBatchPatchIsAllOrNothing(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("a", "A"), testRecipe("b", "B"))
+
+	w := serve(router, http.MethodPatch, "/recipes/batch", `{"ids":["a","b"],"patch":{"category":"snack food"}}`)
+	expectStatus(t, w, http.StatusUnprocessableEntity)
+	for _, id := range []string{"a", "b"} {
+		if got := storedRecipe(t, id).Category; got != "" {
+			t.Errorf("recipe %s category = %q after a rejected batch", id, got)
+		}
+	}
+}
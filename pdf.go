@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+)
+
+// recipePDF renders r as a printable A4 page: the name, a line of servings
+// and times, the ingredients as a list and the instructions numbered. Text
+// is set in the core Helvetica font, so characters outside Windows-1252 do
+// not print.
+func recipePDF(r Recipe) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(r.Name, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.MultiCell(0, 10, tr(r.Name), "", "L", false)
+
+	var facts []string
+	if r.Servings > 0 {
+		facts = append(facts, fmt.Sprintf("Serves %d", r.Servings))
+	} else if r.YieldText != "" {
+		facts = append(facts, r.YieldText)
+	}
+	if r.PrepTime > 0 {
+		facts = append(facts, fmt.Sprintf("Prep %d min", r.PrepTime))
+	}
+	if r.CookTime > 0 {
+		facts = append(facts, fmt.Sprintf("Cook %d min", r.CookTime))
+	}
+	if len(facts) > 0 {
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, 6, tr(strings.Join(facts, "  |  ")), "", "L", false)
+	}
+
+	section := func(title string, lines []string, label func(i int) string) {
+		if len(lines) == 0 {
+			return
+		}
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.MultiCell(0, 8, title, "", "L", false)
+		pdf.SetFont("Helvetica", "", 11)
+		for i, line := range lines {
+			pdf.SetX(20)
+			pdf.CellFormat(8, 6, label(i), "", 0, "L", false, 0, "")
+			pdf.MultiCell(0, 6, tr(line), "", "L", false)
+		}
+	}
+	section("Ingredients", r.Ingredients, func(int) string { return "-" })
+	section("Instructions", r.Instructions, func(i int) string { return fmt.Sprintf("%d.", i+1) })
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportPDFHandler downloads a recipe as a printable PDF.
+//
+// @Summary Export a recipe as PDF
+// @Tags recipes
+// @Produce application/pdf
+// @Param id path string true "Recipe ID"
+// @Success 200 {file} file "PDF document"
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/export.pdf [get]
+func ExportPDFHandler(c *gin.Context) {
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := recipes[i]
+	recipesMu.RUnlock()
+
+	body, err := recipePDF(recipe)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, recipe.ID))
+	c.Data(http.StatusOK, "application/pdf", body)
+}
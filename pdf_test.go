@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestExportPDF(t *testing.T) {
+	r := testRecipe("r1", "Crème brûlée")
+	r.Servings = 4
+	r.PrepTime, r.CookTime = 20, 40
+	r.Ingredients = []string{"500 ml cream", "5 egg yolks", "100 g sugar"}
+	r.Instructions = []string{"Heat the cream.", "Whisk in the yolks and sugar.", "Bake in a water bath."}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/export.pdf", "")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="r1.pdf"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	body := w.Body.Bytes()
+	if !bytes.HasPrefix(body, []byte("%PDF-")) || !bytes.Contains(body[len(body)-32:], []byte("%%EOF")) {
+		t.Errorf("body is not a complete PDF: starts %q", body[:min(len(body), 16)])
+	}
+	if len(body) < 1000 {
+		t.Errorf("PDF is only %d bytes", len(body))
+	}
+
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/export.pdf", ""), http.StatusNotFound)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setPinned pins or unpins the recipe named in the path.
+func setPinned(c *gin.Context, pinned bool, action string) {
+	recipesMu.Lock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := recipes[i]
+	if recipe.Pinned != pinned {
+		recipe.Pinned = pinned
+		recipe.UpdatedAt = Timestamp{time.Now()}
+		replaceRecipe(i, recipe)
+	}
+	recipesMu.Unlock()
+
+	auditor.record(c, action, recipe.ID)
+	c.JSON(http.StatusOK, fresh(recipe))
+}
+
+// PinRecipeHandler features a recipe at the top of the recipe list.
+//
+// @Summary Pin a recipe
+// @Tags recipes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} Recipe
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/pin [post]
+func PinRecipeHandler(c *gin.Context) {
+	setPinned(c, true, "pin")
+}
+
+// UnpinRecipeHandler returns a pinned recipe to its normal place in the list.
+//
+// @Summary Unpin a recipe
+// @Tags recipes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} Recipe
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/unpin [post]
+func UnpinRecipeHandler(c *gin.Context) {
+	setPinned(c, false, "unpin")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestPinnedRecipesComeFirst(t *testing.T) {
+	router := newTestRouter(t, withUsers,
+		testRecipe("a", "Apple pie"), testRecipe("b", "Bread"), testRecipe("c", "Curry"), testRecipe("d", "Dal"))
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/d/pin", ""), http.StatusUnauthorized)
+	for _, id := range []string{"d", "b"} {
+		w := serve(router, http.MethodPost, "/recipe/"+id+"/pin", "", "X-API-KEY", "alice-key")
+		expectStatus(t, w, http.StatusOK)
+		if !decodeBody[Recipe](t, w).Pinned {
+			t.Errorf("pinning %s did not set pinned", id)
+		}
+	}
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/missing/pin", "", "X-API-KEY", "alice-key"), http.StatusNotFound)
+
+	if got := listIDs(t, router, "/recipes"); !slices.Equal(got[:2], []string{"b", "d"}) {
+		t.Errorf("default list = %q, want pinned [b d] first", got)
+	}
+	for target, want := range map[string][]string{
+		"/recipes?sort=name&order=asc":      {"b", "d", "a", "c"},
+		"/recipes?sort=name&order=desc":     {"b", "d", "c", "a"},
+		"/recipes?sort=updatedAt&order=asc": {"b", "d", "a", "c"},
+	} {
+		if got := listIDs(t, router, target); !slices.Equal(got, want) {
+			t.Errorf("%s = %q, want %q", target, got, want)
+		}
+	}
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipe/b/unpin", "", "X-API-KEY", "alice-key"), http.StatusOK)
+	if got, want := listIDs(t, router, "/recipes?sort=name&order=asc"), []string{"d", "a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("after unpinning b: %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPollTimeout = 30 * time.Second
+	maxPollTimeout     = 2 * time.Minute
+	// pollWriteSlack is the time left to write the response once a long
+	// poll stops waiting.
+	pollWriteSlack = 10 * time.Second
+)
+
+// PollResponse is the result of a long poll: the changes after the cursor
+// it was given, oldest first, and the cursor to send next time.
+type PollResponse struct {
+	Changes []Change `json:"changes"`
+	Cursor  string   `json:"cursor"`
+}
+
+// PollChangesHandler is the changes feed for clients that cannot use
+// server-sent events. It returns as soon as there are changes after ?since=
+// (or after the request arrived, without since), or with no changes once
+// ?timeout= elapses. A client that disconnects stops the wait.
+//
+// @Summary Long-poll the changes feed
+// @Tags sync
+// @Produce json
+// @Param since query string false "Cursor from the previous poll, an RFC 3339 timestamp (default: now)"
+// @Param timeout query string false "How long to wait for a change, e.g. 30s (default 30s, at most 2m)"
+// @Success 200 {object} PollResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse "since is older than the tombstones kept; resync without since"
+// @Router /recipes/poll [get]
+func PollChangesHandler(c *gin.Context) {
+	since, ok, err := parseSince(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 timestamp"})
+		return
+	}
+	if !ok {
+		since = time.Now()
+	}
+	timeout := defaultPollTimeout
+	if v := c.Query("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 || d > maxPollTimeout {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "timeout must be a duration of at most 2m"})
+			return
+		}
+		timeout = d
+	}
+	// The wait may outlast WRITE_TIMEOUT, so push this response's deadline
+	// past it. Where the deadline cannot be moved, wait only as long as
+	// still leaves time to write the response before the server's.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + pollWriteSlack)); err != nil {
+		if limit := max(0, config.Server.WriteTimeout-pollWriteSlack); config.Server.WriteTimeout > 0 && timeout > limit {
+			log.Printf("poll: cannot extend write deadline (%v); waiting %s instead of %s", err, limit, timeout)
+			timeout = limit
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		recipesMu.RLock()
+		expired := sinceExpired(since)
+		changes := changesSince(since, true)
+		wait := changeSignal
+		recipesMu.RUnlock()
+
+		if expired {
+			c.JSON(http.StatusGone, gin.H{"error": errResyncRequired})
+			return
+		}
+		if len(changes) > 0 {
+			cursor := changes[len(changes)-1].UpdatedAt
+			c.JSON(http.StatusOK, PollResponse{Changes: changes, Cursor: cursor.Format(time.RFC3339Nano)})
+			return
+		}
+		select {
+		case <-wait:
+		case <-timer.C:
+			c.JSON(http.StatusOK, PollResponse{Changes: changes, Cursor: since.Format(time.RFC3339Nano)})
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
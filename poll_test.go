@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPollReturnsChangeDuringWait(t *testing.T) {
+	router := newTestRouter(t, nil)
+	since := time.Now().UTC().Add(-time.Millisecond).Format(time.RFC3339Nano)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- serve(router, http.MethodGet, "/recipes/poll?timeout=10s&since="+url.QueryEscape(since), "")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	w := serve(router, http.MethodPost, "/recipes", newRecipeBody)
+	expectStatus(t, w, http.StatusCreated)
+	created := decodeBody[Recipe](t, w)
+
+	select {
+	case w = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("poll did not return after a change")
+	}
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[PollResponse](t, w)
+	if len(got.Changes) != 1 || got.Changes[0].ID != created.ID || got.Changes[0].Deleted {
+		t.Fatalf("changes = %+v, want the creation of %s", got.Changes, created.ID)
+	}
+	cursor, err := time.Parse(time.RFC3339Nano, got.Cursor)
+	if err != nil || !cursor.Equal(got.Changes[0].UpdatedAt) {
+		t.Errorf("cursor = %q, want the change's time %v", got.Cursor, got.Changes[0].UpdatedAt)
+	}
+
+	// Polling from the new cursor waits for the next change.
+	w = serve(router, http.MethodGet, "/recipes/poll?timeout=10ms&since="+url.QueryEscape(got.Cursor), "")
+	expectStatus(t, w, http.StatusOK)
+	if next := decodeBody[PollResponse](t, w); len(next.Changes) != 0 {
+		t.Errorf("poll from the cursor returned %+v again", next.Changes)
+	}
+}
+
+func TestPollTimesOutWithoutChanges(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	since := time.Now().UTC().Format(time.RFC3339Nano)
+
+	start := time.Now()
+	w := serve(router, http.MethodGet, "/recipes/poll?timeout=50ms&since="+url.QueryEscape(since), "")
+	if took := time.Since(start); took < 50*time.Millisecond {
+		t.Errorf("poll returned after %v, before the timeout", took)
+	}
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[PollResponse](t, w)
+	if got.Changes == nil || len(got.Changes) != 0 || got.Cursor != since {
+		t.Errorf("got %+v, want no changes and the cursor unchanged", got)
+	}
+
+	for _, q := range []string{"timeout=soon", "timeout=-1s", "timeout=5m", "since=yesterday"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipes/poll?"+q, ""), http.StatusBadRequest)
+	}
+}
+
+func TestPollStopsOnDisconnect(t *testing.T) {
+	router := newTestRouter(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/recipes/poll?timeout=1m", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(w, req)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("poll kept waiting after the client went away")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("wrote %q to a disconnected client", w.Body.String())
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 1024
+)
+
+// recipeURL is the absolute link to the recipe with id; see publicBaseURL.
+func recipeURL(c *gin.Context, id string) string {
+	return publicBaseURL(c) + "/recipe/" + url.PathEscape(id)
+}
+
+// RecipeQRHandler serves a PNG QR code linking to a recipe, for printed
+// recipe cards.
+//
+// @Summary QR code linking to a recipe
+// @Tags recipes
+// @Produce image/png
+// @Param id path string true "Recipe ID"
+// @Param size query int false "Width and height in pixels (default 256, 64 to 1024)"
+// @Success 200 {file} file "PNG image"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/qr [get]
+func RecipeQRHandler(c *gin.Context) {
+	size := defaultQRSize
+	if v := c.Query("size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < minQRSize || n > maxQRSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "size must be an integer from 64 to 1024"})
+			return
+		}
+		size = n
+	}
+
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	id := recipes[i].ID
+	recipesMu.RUnlock()
+
+	png, err := qrcode.Encode(recipeURL(c, id), qrcode.Medium, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"testing"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+func TestRecipeQR(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) { cfg.PublicBaseURL = "https://recipes.example" }, testRecipe("r1", "Soup"))
+
+	w := serve(router, http.MethodGet, "/recipe/r1/qr", "")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", ct)
+	}
+	img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != defaultQRSize || b.Dy() != defaultQRSize {
+		t.Errorf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), defaultQRSize, defaultQRSize)
+	}
+	want, err := qrcode.Encode("https://recipes.example/recipe/r1", qrcode.Medium, defaultQRSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(w.Body.Bytes(), want) {
+		t.Error("QR code does not encode https://recipes.example/recipe/r1")
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/r1/qr?size=512", "")
+	expectStatus(t, w, http.StatusOK)
+	img, err = png.Decode(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if got := img.Bounds().Dx(); got != 512 {
+		t.Errorf("size=512 gave an image %d pixels wide", got)
+	}
+}
+
+func TestRecipeQRUsesRequestHost(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	w := serve(router, http.MethodGet, "/recipe/r1/qr", "", "X-Forwarded-Proto", "https")
+	expectStatus(t, w, http.StatusOK)
+	want, err := qrcode.Encode("https://example.com/recipe/r1", qrcode.Medium, defaultQRSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(w.Body.Bytes(), want) {
+		t.Error("QR code does not encode https://example.com/recipe/r1")
+	}
+}
+
+func TestRecipeQRErrors(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/qr", ""), http.StatusNotFound)
+	for _, size := range []string{"10", "2048", "big"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipe/r1/qr?size="+size, ""), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// plausibleQuantity is the largest amount of each canonical unit, keyed as
+// in kitchenUnits, that a home recipe plausibly calls for in one ingredient
+// line. The empty key is for bare counts such as "2 eggs". Anything above it
+// is more likely a typo, such as grams entered as kilograms.
+var plausibleQuantity = map[string]float64{
+	"":      100,
+	"cup":   20,
+	"tbsp":  32,
+	"tsp":   48,
+	"g":     5000,
+	"kg":    5,
+	"ml":    5000,
+	"l":     5,
+	"oz":    160,
+	"lb":    10,
+	"pinch": 10,
+	"clove": 40,
+}
+
+// QuantityCheck is the result of checking a recipe's ingredient quantities.
+type QuantityCheck struct {
+	ID       string   `json:"id"`
+	Warnings []string `json:"warnings"`
+}
+
+// checkQuantities warns about ingredient lines whose parsed quantity is
+// zero or larger than plausibleQuantity allows. Lines without a leading
+// quantity, such as "salt to taste", are not checked.
+func checkQuantities(r Recipe) []string {
+	warnings := make([]string, 0)
+	for i, line := range r.Ingredients {
+		qty, unit, _, ok := parseIngredient(line)
+		if !ok {
+			continue
+		}
+		switch limit := plausibleQuantity[unit]; {
+		case qty == 0:
+			warnings = append(warnings, fmt.Sprintf("ingredient %d %q has a zero quantity", i+1, line))
+		case qty > limit:
+			what := unit
+			if what == "" {
+				what = "items"
+			}
+			warnings = append(warnings, fmt.Sprintf("ingredient %d %q is more than %s %s, which looks implausible", i+1, line, formatQuantity(limit), what))
+		}
+	}
+	return warnings
+}
+
+// QuantityCheckHandler flags suspicious ingredient quantities in a recipe,
+// such as "0 cups" or an implausibly large amount, to catch data-entry
+// errors. It only reports; nothing is rejected or changed.
+//
+// @Summary Check a recipe's ingredient quantities
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} QuantityCheck
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/quantity-check [get]
+func QuantityCheckHandler(c *gin.Context) {
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := recipes[i]
+	recipesMu.RUnlock()
+
+	c.JSON(http.StatusOK, QuantityCheck{ID: recipe.ID, Warnings: checkQuantities(recipe)})
+}
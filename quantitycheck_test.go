@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestQuantityCheck(t *testing.T) {
+	r := testRecipe("r1", "Cake")
+	r.Ingredients = []string{
+		"0 cups sugar",
+		"2 cups flour",
+		"2000 g butter",
+		"20 kg flour",
+		"150 eggs",
+		"salt to taste",
+	}
+	router := newTestRouter(t, nil, r, testRecipe("ok", "Bread"))
+
+	w := serve(router, http.MethodGet, "/recipe/r1/quantity-check", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[QuantityCheck](t, w)
+	if got.ID != "r1" || len(got.Warnings) != 3 {
+		t.Fatalf("got %+v, want 3 warnings for r1", got)
+	}
+	for i, want := range []string{`ingredient 1 "0 cups sugar" has a zero quantity`, `ingredient 4 "20 kg flour" is more than 5 kg`, `ingredient 5 "150 eggs" is more than 100 items`} {
+		if !strings.HasPrefix(got.Warnings[i], want) {
+			t.Errorf("warning %d = %q, want it to start %q", i, got.Warnings[i], want)
+		}
+	}
+	if stored := storedRecipe(t, "r1"); stored.Ingredients[0] != "0 cups sugar" {
+		t.Errorf("the check changed the recipe to %q", stored.Ingredients)
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/ok/quantity-check", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[QuantityCheck](t, w).Warnings; got == nil || len(got) != 0 {
+		t.Errorf("plausible recipe warnings = %q, want an empty list", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/quantity-check", ""), http.StatusNotFound)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig sets the API-wide request rate per second for anonymous
+// clients, per IP, and for authenticated users, per user. Zero means
+// unlimited.
+type RateLimitConfig struct {
+	RPS     float64
+	AuthRPS float64
+}
+
+// minClientIdle is the shortest time a client's bucket is kept after its
+// last request.
+const minClientIdle = 10 * time.Minute
+
+// rateLimiter hands out a token bucket per client key. Authenticated users
+// get buckets of authLimit and authBurst, everyone else of limit and burst.
+// Buckets of clients that have gone quiet are dropped as other requests
+// come in, so rotating IPs cannot grow the map without bound.
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	authLimit rate.Limit
+	authBurst int
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+	// keys maps API keys to users, so a limiter that runs before
+	// AuthMiddleware can still tell authenticated users apart.
+	keys map[string]string
+}
+
+// clientBucket is one client's token bucket and when it last made a request.
+type clientBucket struct {
+	lim  *rate.Limiter
+	seen time.Time
+}
+
+// newRateLimiter limits every client, authenticated or not, to limit with
+// bursts of burst.
+func newRateLimiter(limit rate.Limit, burst int) *rateLimiter {
+	return &rateLimiter{limit: limit, burst: burst, authLimit: limit, authBurst: burst, clients: make(map[string]*clientBucket)}
+}
+
+// newRequestRateLimiter builds the API-wide limiter from cfg, allowing
+// bursts of one second's worth of requests. keys are the API keys of
+// AuthMiddleware, for telling authenticated users apart.
+func newRequestRateLimiter(cfg RateLimitConfig, keys map[string]string) *rateLimiter {
+	l := newRateLimiter(perSecond(cfg.RPS))
+	l.authLimit, l.authBurst = perSecond(cfg.AuthRPS)
+	l.keys = keys
+	return l
+}
+
+// perSecond converts a rate in requests per second, zero meaning unlimited,
+// to a limit and a burst of one second's worth.
+func perSecond(rps float64) (rate.Limit, int) {
+	if rps <= 0 {
+		return rate.Inf, 0
+	}
+	return rate.Limit(rps), int(math.Max(1, math.Ceil(rps)))
+}
+
+// idleAfter is how long a client must be quiet before its bucket is
+// dropped: long enough for any bucket to have refilled, so a client that
+// returns gets the same allowance it would have had anyway.
+func (l *rateLimiter) idleAfter() time.Duration {
+	idle := minClientIdle
+	for _, b := range []struct {
+		limit rate.Limit
+		burst int
+	}{{l.limit, l.burst}, {l.authLimit, l.authBurst}} {
+		if b.limit > 0 && b.limit != rate.Inf {
+			idle = max(idle, time.Duration(float64(b.burst)/float64(b.limit)*float64(time.Second)))
+		}
+	}
+	return idle
+}
+
+// sweep drops the buckets of clients idle for longer than idleAfter. It
+// scans the map at most once per idle period. Callers must hold l.mu.
+func (l *rateLimiter) sweep(now time.Time) {
+	idle := l.idleAfter()
+	if now.Sub(l.lastSweep) < idle {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.clients {
+		if now.Sub(b.seen) > idle {
+			delete(l.clients, key)
+		}
+	}
+}
+
+func (l *rateLimiter) allow(key string, authenticated bool) bool {
+	now := clock()
+	l.mu.Lock()
+	l.sweep(now)
+	b, ok := l.clients[key]
+	if !ok {
+		b = &clientBucket{}
+		if authenticated {
+			b.lim = rate.NewLimiter(l.authLimit, l.authBurst)
+		} else {
+			b.lim = rate.NewLimiter(l.limit, l.burst)
+		}
+		l.clients[key] = b
+	}
+	b.seen = now
+	l.mu.Unlock()
+	return b.lim.AllowN(now, 1)
+}
+
+// clientKey identifies the caller for rate limiting: the user authenticated
+// by AuthMiddleware or, before it has run, by a valid X-API-KEY in l.keys;
+// otherwise the client IP. A request with an unknown key counts against its
+// IP.
+func (l *rateLimiter) clientKey(c *gin.Context) (key string, authenticated bool) {
+	user, ok := currentUser(c)
+	if key := c.GetHeader("X-API-KEY"); !ok && key != "" {
+		user, ok = l.keys[key]
+	}
+	if ok {
+		return "user:" + user, true
+	}
+	return "ip:" + c.ClientIP(), false
+}
+
+// Middleware rejects requests beyond the limit with 429. The API-wide
+// limiter runs before AuthMiddleware, so requests with an invalid key are
+// limited like any other and guessing keys is throttled per IP.
+func (l *rateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.allow(l.clientKey(c)) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// requestsAllowed sends n requests to /recipes with headers and counts
+// those not rejected with 429.
+func requestsAllowed(t *testing.T, router http.Handler, n int, headers ...string) int {
+	t.Helper()
+	allowed := 0
+	for range n {
+		w := serve(router, http.MethodGet, "/recipes", "", headers...)
+		if w.Code != http.StatusTooManyRequests {
+			expectStatus(t, w, http.StatusOK)
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestAuthenticatedRateLimit(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.RateLimit = RateLimitConfig{RPS: 2, AuthRPS: 5}
+	})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+
+	if got := requestsAllowed(t, router, 10); got != 2 {
+		t.Errorf("anonymous client allowed %d requests, want 2", got)
+	}
+	if got := requestsAllowed(t, router, 10, "X-API-KEY", "alice-key"); got != 5 {
+		t.Errorf("alice allowed %d requests, want 5", got)
+	}
+	// Users are limited by user, not by the IP they share.
+	if got := requestsAllowed(t, router, 10, "X-API-KEY", "bob-key"); got != 5 {
+		t.Errorf("bob allowed %d requests, want 5", got)
+	}
+
+	now = now.Add(time.Second)
+	if got := requestsAllowed(t, router, 10); got != 2 {
+		t.Errorf("anonymous client allowed %d requests after refill, want 2", got)
+	}
+}
+
+func TestInvalidKeysAreRateLimited(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.RateLimit = RateLimitConfig{RPS: 2, AuthRPS: 5}
+	})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+
+	var codes []int
+	for i := range 4 {
+		w := serve(router, http.MethodGet, "/recipes", "", "X-API-KEY", "guess-"+strconv.Itoa(i))
+		codes = append(codes, w.Code)
+	}
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusTooManyRequests}
+	if !slices.Equal(codes, want) {
+		t.Errorf("statuses for bad keys = %v, want %v", codes, want)
+	}
+	// Bad keys spend the IP's allowance, not a user's.
+	if got := requestsAllowed(t, router, 1); got != 0 {
+		t.Errorf("anonymous request after bad keys allowed %d, want 0", got)
+	}
+	if got := requestsAllowed(t, router, 10, "X-API-KEY", "alice-key"); got != 5 {
+		t.Errorf("alice allowed %d requests after bad keys, want 5", got)
+	}
+}
+
+func TestRateLimiterEvictsIdleClients(t *testing.T) {
+	newTestRouter(t, nil)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+	l := newRequestRateLimiter(RateLimitConfig{RPS: 1, AuthRPS: 1}, nil)
+
+	l.allow("ip:1", false)
+	now = now.Add(minClientIdle / 2)
+	l.allow("ip:2", false)
+	now = now.Add(minClientIdle/2 + time.Second)
+	l.allow("ip:3", false)
+
+	for key, want := range map[string]bool{"ip:1": false, "ip:2": true, "ip:3": true} {
+		if _, ok := l.clients[key]; ok != want {
+			t.Errorf("bucket for %s kept = %v, want %v", key, ok, want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Recipe is a single recipe as stored and served by the API. PrepTime and
+// CookTime are in minutes. Steps is the structured form of Instructions;
+// when a client sends only Steps, Instructions is filled from their text so
+// older clients and the text-based features keep working. CostCents is an
+// estimated cost in the minor unit of Currency, an ISO 4217 code; a recipe
+// without a currency is unpriced. Language is the BCP 47 tag of the base
+// text fields and Translations holds them in other languages, keyed by tag;
+// see localizeRecipe. ParentID is the recipe this one was forked from, set
+// by the fork endpoint and cleared when the parent is deleted. Thumbnail is
+// a JPEG data URI managed by the image upload endpoint and Pinned is set by
+// the pin endpoints; values sent by clients for these three are ignored.
+// Freshness is computed when the recipe is served; see recipeFreshness.
+type Recipe struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Tags         []string               `json:"tags"`
+	Category     string                 `json:"category,omitempty"`
+	Ingredients  []string               `json:"ingredients"`
+	Instructions []string               `json:"instructions"`
+	Steps        []Step                 `json:"steps,omitempty"`
+	Allergens    []string               `json:"allergens,omitempty"`
+	Equipment    []string               `json:"equipment,omitempty"`
+	Videos       []Video                `json:"videos,omitempty"`
+	Difficulty   string                 `json:"difficulty,omitempty"`
+	PrepTime     int                    `json:"prepTime,omitempty"`
+	CookTime     int                    `json:"cookTime,omitempty"`
+	Servings     int                    `json:"servings,omitempty"`
+	YieldText    string                 `json:"yieldText,omitempty"`
+	CostCents    int                    `json:"costCents,omitempty"`
+	Currency     string                 `json:"currency,omitempty"`
+	Language     string                 `json:"language,omitempty"`
+	Translations map[string]Translation `json:"translations,omitempty"`
+	ParentID     string                 `json:"parentId,omitempty"`
+	Thumbnail    string                 `json:"thumbnail,omitempty"`
+	Pinned       bool                   `json:"pinned,omitempty"`
+	Freshness    string                 `json:"freshness"`
+	PublishedAt  Timestamp              `json:"publishedAt" swaggertype:"string" format:"date-time" extensions:"x-nullable"`
+	UpdatedAt    Timestamp              `json:"updatedAt" swaggertype:"string" format:"date-time" extensions:"x-nullable"`
+}
+
+// Video is a companion video for a recipe, such as a YouTube link.
+type Video struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Step is one structured instruction, with an optional timer in seconds and
+// oven or hob temperature in degrees Celsius.
+type Step struct {
+	Text            string `json:"text"`
+	DurationSeconds int    `json:"durationSeconds,omitempty"`
+	TemperatureC    int    `json:"temperatureC,omitempty"`
+}
+
+// knownAllergens is the closed set of values accepted in Recipe.Allergens.
+var knownAllergens = map[string]bool{
+	"celery":      true,
+	"crustaceans": true,
+	"dairy":       true,
+	"eggs":        true,
+	"fish":        true,
+	"gluten":      true,
+	"lupin":       true,
+	"molluscs":    true,
+	"mustard":     true,
+	"nuts":        true,
+	"peanuts":     true,
+	"sesame":      true,
+	"soy":         true,
+	"sulphites":   true,
+}
+
+// knownCategories is the closed set of values accepted in Recipe.Category.
+var knownCategories = map[string]bool{
+	"breakfast": true,
+	"starter":   true,
+	"main":      true,
+	"side":      true,
+	"dessert":   true,
+	"snack":     true,
+	"drink":     true,
+}
+
+// knownDifficulties is the closed set of values accepted in Recipe.Difficulty.
+var knownDifficulties = map[string]bool{
+	"easy":   true,
+	"medium": true,
+	"hard":   true,
+}
+
+// normalizeList lower-cases and trims each entry, dropping blanks and
+// duplicates while keeping first-seen order.
+func normalizeList(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// normalizeRecipe canonicalises client-supplied fields in place.
+func normalizeRecipe(r *Recipe) {
+	r.Category = strings.ToLower(strings.TrimSpace(r.Category))
+	r.Difficulty = strings.ToLower(strings.TrimSpace(r.Difficulty))
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	r.Tags = normalizeTags(r.Tags)
+	r.Allergens = normalizeList(r.Allergens)
+	r.Equipment = normalizeList(r.Equipment)
+	for i := range r.Videos {
+		r.Videos[i].Title = strings.TrimSpace(r.Videos[i].Title)
+		r.Videos[i].URL = strings.TrimSpace(r.Videos[i].URL)
+	}
+	for i := range r.Steps {
+		r.Steps[i].Text = strings.TrimSpace(r.Steps[i].Text)
+	}
+	normalizeTranslations(r)
+	if len(r.Instructions) == 0 && len(r.Steps) > 0 {
+		r.Instructions = make([]string, len(r.Steps))
+		for i, s := range r.Steps {
+			r.Instructions[i] = s.Text
+		}
+	}
+	bannedWords.maskRecipe(r)
+}
+
+// validateRecipe reports the first problem with a normalized recipe.
+func validateRecipe(r *Recipe) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.Category != "" && !knownCategories[r.Category] {
+		return fmt.Errorf("unknown category %q (allowed: %s)", r.Category, strings.Join(sortedKeys(knownCategories), ", "))
+	}
+	if r.Difficulty != "" && !knownDifficulties[r.Difficulty] {
+		return fmt.Errorf("unknown difficulty %q (allowed: easy, medium, hard)", r.Difficulty)
+	}
+	if r.PrepTime < 0 || r.CookTime < 0 {
+		return fmt.Errorf("prepTime and cookTime must not be negative")
+	}
+	if r.CostCents < 0 {
+		return fmt.Errorf("costCents must not be negative")
+	}
+	if r.Currency != "" && !isCurrencyCode(r.Currency) {
+		return fmt.Errorf("currency %q must be a 3-letter code such as USD", r.Currency)
+	}
+	if r.CostCents > 0 && r.Currency == "" {
+		return fmt.Errorf("currency is required with costCents")
+	}
+	for i, v := range r.Videos {
+		if err := validateVideoURL(v.URL); err != nil {
+			return fmt.Errorf("videos[%d]: %v", i, err)
+		}
+	}
+	for i, s := range r.Steps {
+		switch {
+		case s.Text == "":
+			return fmt.Errorf("steps[%d]: text is required", i)
+		case s.DurationSeconds < 0:
+			return fmt.Errorf("steps[%d]: durationSeconds must not be negative", i)
+		case s.TemperatureC < 0:
+			return fmt.Errorf("steps[%d]: temperatureC must not be negative", i)
+		}
+	}
+	if err := validateTranslations(r); err != nil {
+		return err
+	}
+	if err := bannedWords.check(r); err != nil {
+		return err
+	}
+	var unknown []string
+	for _, a := range r.Allergens {
+		if !knownAllergens[a] {
+			unknown = append(unknown, a)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown allergens: %s (allowed: %s)", strings.Join(unknown, ", "), strings.Join(sortedKeys(knownAllergens), ", "))
+	}
+	return nil
+}
+
+// validateVideoURL accepts only absolute http and https URLs with a host.
+func validateVideoURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("malformed url %q", raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url %q must use http or https", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url %q has no host", raw)
+	}
+	return nil
+}
+
+// isCurrencyCode reports whether s has the shape of an upper-case ISO 4217
+// code. The code itself is not checked against the standard's list.
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
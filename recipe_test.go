@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestVideoURLValidation(t *testing.T) {
+	router := newTestRouter(t, nil)
+	post := func(videos string) *httptest.ResponseRecorder {
+		return serve(router, http.MethodPost, "/recipes",
+			`{"name":"Bread","ingredients":["flour"],"instructions":["Bake."],"videos":`+videos+`}`)
+	}
+
+	w := post(`[{"title":"Shaping","url":" https://www.youtube.com/watch?v=abc "},{"url":"http://example.com/knead.mp4"}]`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := decodeBody[Recipe](t, w).Videos; len(got) != 2 || got[0].URL != "https://www.youtube.com/watch?v=abc" {
+		t.Errorf("videos = %+v, want both with trimmed URLs", got)
+	}
+
+	for _, bad := range []string{
+		`[{"title":"No URL"}]`,
+		`[{"url":"ftp://example.com/video"}]`,
+		`[{"url":"javascript:alert(1)"}]`,
+		`[{"url":"https://"}]`,
+		`[{"url":"http://exa mple.com/%zz"}]`,
+		`[{"url":"youtube.com/watch?v=abc"}]`,
+	} {
+		expectStatus(t, post(bad), http.StatusUnprocessableEntity)
+	}
+}
+
+func TestStructuredStepsRoundTrip(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	body := `{"name":"Roast","ingredients":["1 chicken"],"steps":[` +
+		`{"text":" Preheat the oven. ","temperatureC":200},` +
+		`{"text":"Roast the chicken.","durationSeconds":4500,"temperatureC":200}]}`
+	w := serve(router, http.MethodPost, "/recipes", body)
+	expectStatus(t, w, http.StatusCreated)
+	id := decodeBody[Recipe](t, w).ID
+
+	w = serve(router, http.MethodGet, "/recipe/"+id, "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[Recipe](t, w)
+	want := []Step{
+		{Text: "Preheat the oven.", TemperatureC: 200},
+		{Text: "Roast the chicken.", DurationSeconds: 4500, TemperatureC: 200},
+	}
+	if !slices.Equal(got.Steps, want) {
+		t.Errorf("steps = %+v, want %+v", got.Steps, want)
+	}
+	if want := []string{"Preheat the oven.", "Roast the chicken."}; !slices.Equal(got.Instructions, want) {
+		t.Errorf("instructions = %q, want them filled from the steps: %q", got.Instructions, want)
+	}
+}
+
+func TestLegacyInstructionsWithoutSteps(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Bread"))
+
+	w := serve(router, http.MethodGet, "/recipe/r1", "")
+	expectStatus(t, w, http.StatusOK)
+	if _, ok := decodeBody[map[string]any](t, w)["steps"]; ok {
+		t.Error("a recipe without steps returned a steps field")
+	}
+
+	body := `{"name":"Bread","ingredients":["flour"],"instructions":["Knead well."],"steps":[{"text":"Knead.","durationSeconds":600}]}`
+	w = serve(router, http.MethodPut, "/recipe/r1", body)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[Recipe](t, w)
+	if !slices.Equal(got.Instructions, []string{"Knead well."}) || len(got.Steps) != 1 {
+		t.Errorf("got instructions %q and steps %+v, want both kept as sent", got.Instructions, got.Steps)
+	}
+}
+
+func TestStepValidation(t *testing.T) {
+	router := newTestRouter(t, nil)
+	for _, steps := range []string{
+		`[{"text":"Bake.","durationSeconds":-60}]`,
+		`[{"text":"Bake.","temperatureC":-5}]`,
+		`[{"text":"Bake."},{"text":"  ","durationSeconds":60}]`,
+	} {
+		w := serve(router, http.MethodPost, "/recipes", `{"name":"Cake","ingredients":["flour"],"steps":`+steps+`}`)
+		expectStatus(t, w, http.StatusUnprocessableEntity)
+	}
+}
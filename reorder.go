@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// firstMention returns the index in words of the first mention of name, or
+// -1. The whole name counts as a mention, and so does its last word on its
+// own, so "olive oil" is found in "heat the oil".
+func firstMention(words, name []string) int {
+	if len(name) == 0 {
+		return -1
+	}
+	for i := range words {
+		if i+len(name) <= len(words) && slices.Equal(words[i:i+len(name)], name) {
+			return i
+		}
+	}
+	head := name[len(name)-1]
+	return slices.Index(words, head)
+}
+
+// reorderIngredients returns r's ingredient lines ordered by where their
+// names are first mentioned in the instructions, compared word by word as
+// the linter does. Lines never mentioned keep their original order at the
+// end. r itself is not modified.
+func reorderIngredients(r Recipe) []string {
+	var words []string
+	for _, step := range r.Instructions {
+		words = append(words, ingredientWords(step)...)
+	}
+	type ranked struct {
+		line string
+		pos  int
+	}
+	lines := make([]ranked, len(r.Ingredients))
+	for i, line := range r.Ingredients {
+		pos := firstMention(words, ingredientWords(ingredientName(line)))
+		if pos < 0 {
+			pos = len(words)
+		}
+		lines[i] = ranked{line, pos}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].pos < lines[j].pos })
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.line
+	}
+	return out
+}
+
+// ReorderIngredientsHandler returns a copy of a recipe with its ingredients
+// listed in the order the instructions first use them. The stored recipe is
+// not changed.
+//
+// @Summary Order ingredients by first use
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} Recipe
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/reorder-ingredients [get]
+func ReorderIngredientsHandler(c *gin.Context) {
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	recipe := recipes[i]
+	recipesMu.RUnlock()
+
+	recipe.Ingredients = reorderIngredients(recipe)
+	respondRecipe(c, http.StatusOK, recipe)
+}
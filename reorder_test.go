@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestReorderIngredients(t *testing.T) {
+	r := testRecipe("r1", "Pasta")
+	r.Ingredients = []string{"200 g spaghetti", "1 pinch salt", "2 tbsp olive oil", "2 cloves garlic", "1 sprig basil"}
+	r.Instructions = []string{
+		"Warm the oil and fry the garlic.",
+		"Boil the spaghetti until tender.",
+		"Season with salt.",
+	}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/reorder-ingredients", "")
+	expectStatus(t, w, http.StatusOK)
+	want := []string{"2 tbsp olive oil", "2 cloves garlic", "200 g spaghetti", "1 pinch salt", "1 sprig basil"}
+	if got := decodeBody[Recipe](t, w).Ingredients; !slices.Equal(got, want) {
+		t.Errorf("ingredients = %q, want %q", got, want)
+	}
+	if got := storedRecipe(t, "r1").Ingredients; !slices.Equal(got, r.Ingredients) {
+		t.Errorf("stored ingredients changed to %q", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/reorder-ingredients", ""), http.StatusNotFound)
+}
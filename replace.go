@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// prepareReplacement normalizes and validates a full replacement collection,
+// giving new entries an ID and timestamps. Provided IDs are kept but must be
+// unique.
+func prepareReplacement(list []Recipe, now time.Time) error {
+	seen := make(map[string]int, len(list))
+	for i := range list {
+		r := &list[i]
+		normalizeRecipe(r)
+		if err := validateRecipe(r); err != nil {
+			return fmt.Errorf("recipe %d: %v", i, err)
+		}
+		if r.ID == "" {
+			r.ID = newRecipeID()
+		}
+		if prev, dup := seen[r.ID]; dup {
+			return fmt.Errorf("recipe %d: id %s already used by recipe %d", i, r.ID, prev)
+		}
+		seen[r.ID] = i
+		if r.PublishedAt.IsZero() {
+			r.PublishedAt = Timestamp{now}
+		}
+		if r.UpdatedAt.IsZero() {
+			r.UpdatedAt = Timestamp{now}
+		}
+	}
+	return nil
+}
+
+// ReplaceRecipesHandler atomically replaces the whole collection with the
+// JSON array in the body. Nothing changes unless every entry is valid.
+//
+// @Summary Replace all recipes
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param recipes body []Recipe true "The new collection"
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse
+// @Router /recipes [put]
+func ReplaceRecipesHandler(c *gin.Context) {
+	if !hasBody(c.Request) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errEmptyBody.Error()})
+		return
+	}
+	var list []Recipe
+	if err := bindJSON(c, &list); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if list == nil {
+		list = make([]Recipe, 0)
+	}
+	if err := prepareReplacement(list, time.Now()); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if config.Capacity.max > 0 && len(list) > config.Capacity.max {
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": fmt.Sprintf("recipe limit of %d exceeded", config.Capacity.max)})
+		return
+	}
+
+	recipesMu.Lock()
+	replaceAllRecipes(list)
+	recipesMu.Unlock()
+
+	auditor.record(c, "replace", "*")
+	c.JSON(http.StatusOK, gin.H{"count": len(list)})
+}
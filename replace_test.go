@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func storedIDs() []string {
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	return sortedIDs(recipes)
+}
+
+func TestReplaceAllRecipes(t *testing.T) {
+	router := newTestRouter(t, withUsers, testRecipe("old1", "Old 1"), testRecipe("old2", "Old 2"))
+	body := `[{"id":"kept","name":"Kept","ingredients":["rice"],"instructions":["Cook."]},` +
+		`{"name":"New","ingredients":["bread"],"instructions":["Toast."]}]`
+
+	expectStatus(t, serve(router, http.MethodPut, "/recipes", body), http.StatusUnauthorized)
+	w := serve(router, http.MethodPut, "/recipes", body, "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	if n := decodeBody[map[string]int](t, w)["count"]; n != 2 {
+		t.Errorf("count = %d, want 2", n)
+	}
+
+	ids := storedIDs()
+	if len(ids) != 2 || !slices.Contains(ids, "kept") || slices.Contains(ids, "old1") || slices.Contains(ids, "old2") {
+		t.Fatalf("stored %q, want kept and one new recipe", ids)
+	}
+	for _, id := range ids {
+		r := storedRecipe(t, id)
+		if r.ID == "" || r.PublishedAt.IsZero() || r.UpdatedAt.IsZero() {
+			t.Errorf("recipe %+v lacks an ID or timestamps", r)
+		}
+	}
+}
+
+func TestReplaceAllIsAtomic(t *testing.T) {
+	router := newTestRouter(t, withUsers, testRecipe("old", "Old"))
+
+	for _, body := range []string{
+		`[{"name":"Good","ingredients":["rice"],"instructions":["Cook."]},{"name":"Bad","category":"nonsense"}]`,
+		`[{"id":"same","name":"A"},{"id":"same","name":"B"}]`,
+	} {
+		expectStatus(t, serve(router, http.MethodPut, "/recipes", body, "X-API-KEY", "alice-key"), http.StatusUnprocessableEntity)
+		if ids := storedIDs(); !slices.Equal(ids, []string{"old"}) {
+			t.Errorf("store = %q after a rejected replacement, want [old]", ids)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScaledRecipe is the response of the scale endpoint.
+type ScaledRecipe struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	OriginalServings int      `json:"originalServings,omitempty"`
+	Servings         int      `json:"servings,omitempty"`
+	YieldText        string   `json:"yieldText,omitempty"`
+	Scaled           bool     `json:"scaled"`
+	Note             string   `json:"note,omitempty"`
+	Ingredients      []string `json:"ingredients"`
+	// Explain is filled on request with how each ingredient line was read.
+	Explain []IngredientExplanation `json:"explain,omitempty"`
+}
+
+// IngredientExplanation shows how scaling read one ingredient line: whether
+// a leading quantity was parsed, the quantity and canonical unit found, and
+// the resulting text. Unparsed lines are passed through unchanged.
+type IngredientExplanation struct {
+	Original string  `json:"original"`
+	Parsed   bool    `json:"parsed"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+	Scaled   string  `json:"scaled"`
+}
+
+// quantityPattern matches a leading quantity such as "2", "1.5", "3/4" or
+// "1 1/2", followed by the rest of the ingredient line.
+var quantityPattern = regexp.MustCompile(`^\s*(\d+\s+\d+/\d+|\d+/\d+|\d+(?:\.\d+)?)\s*(.*)$`)
+
+// parseQuantity splits an ingredient line into its leading quantity and the
+// remaining text. ok is false when the line does not start with a quantity.
+func parseQuantity(line string) (qty float64, rest string, ok bool) {
+	m := quantityPattern.FindStringSubmatch(line)
+	if m == nil {
+		return 0, line, false
+	}
+	total := 0.0
+	for _, part := range strings.Fields(m[1]) {
+		if num, den, found := strings.Cut(part, "/"); found {
+			n, _ := strconv.ParseFloat(num, 64)
+			d, _ := strconv.ParseFloat(den, 64)
+			if d == 0 {
+				return 0, line, false
+			}
+			total += n / d
+			continue
+		}
+		v, _ := strconv.ParseFloat(part, 64)
+		total += v
+	}
+	return total, m[2], true
+}
+
+// kitchenUnits maps recognised unit spellings to their canonical form.
+var kitchenUnits = map[string]string{
+	"cup": "cup", "cups": "cup",
+	"tbsp": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp",
+	"tsp": "tsp", "teaspoon": "tsp", "teaspoons": "tsp",
+	"g": "g", "gram": "g", "grams": "g",
+	"kg": "kg", "kilogram": "kg", "kilograms": "kg",
+	"ml": "ml", "millilitre": "ml", "millilitres": "ml", "milliliter": "ml", "milliliters": "ml",
+	"l": "l", "litre": "l", "litres": "l", "liter": "l", "liters": "l",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"pinch": "pinch", "pinches": "pinch",
+	"clove": "clove", "cloves": "clove",
+}
+
+// parseIngredient splits an ingredient line into quantity, canonical unit and
+// the remaining name. unit is empty when the line has a bare count such as
+// "2 eggs"; ok is false when there is no leading quantity at all.
+func parseIngredient(line string) (qty float64, unit, name string, ok bool) {
+	qty, rest, ok := parseQuantity(line)
+	if !ok {
+		return 0, "", strings.TrimSpace(line), false
+	}
+	first, remainder, _ := strings.Cut(rest, " ")
+	if u, known := kitchenUnits[strings.ToLower(strings.TrimSuffix(first, "."))]; known {
+		return qty, u, strings.TrimSpace(remainder), true
+	}
+	return qty, "", strings.TrimSpace(rest), true
+}
+
+// formatQuantity renders a scaled quantity with at most two decimals.
+func formatQuantity(qty float64) string {
+	return strconv.FormatFloat(math.Round(qty*100)/100, 'f', -1, 64)
+}
+
+// scaleIngredient multiplies the leading quantity of line by factor,
+// rendering it with formatKitchenQuantity and keeping the unit's number in
+// agreement. Lines without a recognisable quantity are returned unchanged.
+func scaleIngredient(line string, factor float64) string {
+	qty, rest, ok := parseQuantity(line)
+	if !ok {
+		return line
+	}
+	_, unit, _, _ := parseIngredient(line)
+	text, value := formatKitchenQuantity(qty*factor, unit)
+	if rest == "" {
+		return text
+	}
+	return text + " " + inflectUnit(rest, value)
+}
+
+// scaleRecipe returns recipe's ingredients scaled to the given servings. A
+// recipe without numeric servings cannot be scaled and is passed through
+// unchanged with an explanatory note.
+func scaleRecipe(recipe Recipe, servings int) ScaledRecipe {
+	scaled := ScaledRecipe{
+		ID:               recipe.ID,
+		Name:             recipe.Name,
+		OriginalServings: recipe.Servings,
+		YieldText:        recipe.YieldText,
+	}
+	if recipe.Servings <= 0 {
+		scaled.Ingredients = recipe.Ingredients
+		scaled.Note = "recipe has no numeric servings; ingredients returned unscaled"
+		return scaled
+	}
+	factor := float64(servings) / float64(recipe.Servings)
+	scaled.Servings = servings
+	scaled.Scaled = true
+	scaled.Ingredients = make([]string, len(recipe.Ingredients))
+	for i, line := range recipe.Ingredients {
+		scaled.Ingredients[i] = scaleIngredient(line, factor)
+	}
+	return scaled
+}
+
+// explainScaling describes how each of scaled's ingredients was derived
+// from recipe's.
+func explainScaling(recipe Recipe, scaled ScaledRecipe) []IngredientExplanation {
+	out := make([]IngredientExplanation, len(recipe.Ingredients))
+	for i, line := range recipe.Ingredients {
+		qty, unit, _, ok := parseIngredient(line)
+		out[i] = IngredientExplanation{Original: line, Parsed: ok, Quantity: qty, Unit: unit, Scaled: scaled.Ingredients[i]}
+	}
+	return out
+}
+
+// ScaleRecipeHandler returns a recipe's ingredients scaled to ?servings=N.
+// With ?explain=true it also reports, line by line, which quantities were
+// parsed and scaled and which lines were passed through as they are.
+//
+// @Summary Scale a recipe
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param servings query int true "Target servings"
+// @Param explain query bool false "Add per-ingredient parse details"
+// @Success 200 {object} ScaledRecipe
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/scale [get]
+func ScaleRecipeHandler(c *gin.Context) {
+	servings, err := strconv.Atoi(c.Query("servings"))
+	if err != nil || servings <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "servings must be a positive integer"})
+		return
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	scaled := scaleRecipe(recipes[i], servings)
+	if c.Query("explain") == "true" {
+		scaled.Explain = explainScaling(recipes[i], scaled)
+	}
+	c.JSON(http.StatusOK, scaled)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestScaleNumericServings(t *testing.T) {
+	r := testRecipe("r1", "Pancakes")
+	r.Servings = 2
+	r.Ingredients = []string{"2 eggs", "1 cup milk", "salt to taste"}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/scale?servings=4", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[ScaledRecipe](t, w)
+	if !got.Scaled || got.Servings != 4 || got.OriginalServings != 2 {
+		t.Fatalf("got %+v, want scaled from 2 to 4 servings", got)
+	}
+	want := []string{"4 eggs", "2 cups milk", "salt to taste"}
+	if !slices.Equal(got.Ingredients, want) {
+		t.Errorf("ingredients = %q, want %q", got.Ingredients, want)
+	}
+}
+
+func TestScaleTextYieldPassesThrough(t *testing.T) {
+	r := testRecipe("r1", "Stew")
+	r.YieldText = "serves 4-6"
+	r.Ingredients = []string{"2 onions"}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/scale?servings=8", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[ScaledRecipe](t, w)
+	if got.Scaled || got.Note == "" {
+		t.Errorf("got scaled=%v note=%q, want unscaled with a note", got.Scaled, got.Note)
+	}
+	if got.YieldText != "serves 4-6" || !slices.Equal(got.Ingredients, r.Ingredients) {
+		t.Errorf("got %+v, want yield text and ingredients unchanged", got)
+	}
+}
+
+func TestScaleRejectsBadServings(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Pancakes"))
+	for _, q := range []string{"", "0", "-1", "two"} {
+		w := serve(router, http.MethodGet, "/recipe/r1/scale?servings="+q, "")
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/scale?servings=2", ""), http.StatusNotFound)
+}
+
+func TestScaleExplain(t *testing.T) {
+	r := testRecipe("r1", "Pancakes")
+	r.Servings = 2
+	r.Ingredients = []string{"1 1/2 cups milk", "2 eggs", "salt to taste", "a pinch of nutmeg"}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/scale?servings=4&explain=true", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[ScaledRecipe](t, w).Explain
+	want := []IngredientExplanation{
+		{Original: "1 1/2 cups milk", Parsed: true, Quantity: 1.5, Unit: "cup", Scaled: "3 cups milk"},
+		{Original: "2 eggs", Parsed: true, Quantity: 2, Scaled: "4 eggs"},
+		{Original: "salt to taste", Scaled: "salt to taste"},
+		{Original: "a pinch of nutmeg", Scaled: "a pinch of nutmeg"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("explain = %+v, want %+v", got, want)
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/r1/scale?servings=4", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[map[string]any](t, w); got["explain"] != nil {
+		t.Errorf("explain returned without ?explain=true: %v", got["explain"])
+	}
+}
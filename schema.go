@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timestampType = reflect.TypeOf(Timestamp{})
+)
+
+// jsonSchema builds a JSON Schema (draft 2020-12) fragment for t from its
+// Go type and json tags. Struct fields without omitempty are listed as
+// required.
+func jsonSchema(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t == timestampType {
+		return map[string]any{"type": []string{"string", "null"}, "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]any)
+		required := make([]string, 0)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = jsonSchema(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{"type": "object", "properties": props, "required": required}
+	}
+	return map[string]any{}
+}
+
+// serverManagedFields are Recipe fields that clients cannot set. They are
+// marked readOnly and dropped from required.
+var serverManagedFields = map[string]bool{
+	"id":          true,
+	"thumbnail":   true,
+	"pinned":      true,
+	"freshness":   true,
+	"publishedAt": true,
+	"updatedAt":   true,
+}
+
+// recipeSchema is the JSON Schema for Recipe: the reflected shape plus the
+// rules validateRecipe and normalizeRecipe enforce.
+func recipeSchema() map[string]any {
+	s := jsonSchema(reflect.TypeOf(Recipe{}))
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "Recipe"
+	props := s["properties"].(map[string]any)
+
+	var required []string
+	for _, name := range s["required"].([]string) {
+		if !serverManagedFields[name] {
+			required = append(required, name)
+		}
+	}
+	s["required"] = required
+	for name := range serverManagedFields {
+		props[name].(map[string]any)["readOnly"] = true
+	}
+
+	props["name"].(map[string]any)["pattern"] = `\S`
+	props["category"].(map[string]any)["enum"] = sortedKeys(knownCategories)
+	props["difficulty"].(map[string]any)["enum"] = sortedKeys(knownDifficulties)
+	props["allergens"].(map[string]any)["items"].(map[string]any)["enum"] = sortedKeys(knownAllergens)
+	for _, name := range []string{"tags", "allergens", "equipment"} {
+		props[name].(map[string]any)["uniqueItems"] = true
+	}
+	for _, name := range []string{"prepTime", "cookTime", "servings", "costCents"} {
+		props[name].(map[string]any)["minimum"] = 0
+	}
+	step := props["steps"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)
+	for _, name := range []string{"durationSeconds", "temperatureC"} {
+		step[name].(map[string]any)["minimum"] = 0
+	}
+	props["currency"].(map[string]any)["pattern"] = "^[A-Z]{3}$"
+	props["freshness"].(map[string]any)["enum"] = []string{"new", "updated", ""}
+	video := props["videos"].(map[string]any)["items"].(map[string]any)
+	video["properties"].(map[string]any)["url"].(map[string]any)["format"] = "uri"
+	return s
+}
+
+var recipeSchemaDoc = recipeSchema()
+
+// RecipeSchemaHandler serves the JSON Schema of a recipe, for clients that
+// generate forms from it.
+//
+// @Summary Recipe JSON Schema
+// @Tags recipes
+// @Produce json
+// @Success 200 {object} map[string]any
+// @Router /recipes/schema [get]
+func RecipeSchemaHandler(c *gin.Context) {
+	c.Header("Content-Type", "application/schema+json")
+	c.JSON(http.StatusOK, recipeSchemaDoc)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// schemaProperty is the part of a property's JSON Schema the tests check.
+type schemaProperty struct {
+	Type        any      `json:"type"`
+	Enum        []string `json:"enum"`
+	ReadOnly    bool     `json:"readOnly"`
+	UniqueItems bool     `json:"uniqueItems"`
+	Items       struct {
+		Enum []string `json:"enum"`
+	} `json:"items"`
+}
+
+func TestRecipeSchema(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodGet, "/recipes/schema", "")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/schema+json") {
+		t.Errorf("Content-Type = %q, want application/schema+json", ct)
+	}
+	s := decodeBody[struct {
+		Type       string                    `json:"type"`
+		Required   []string                  `json:"required"`
+		Properties map[string]schemaProperty `json:"properties"`
+	}](t, w)
+
+	if s.Type != "object" {
+		t.Errorf("type = %q, want object", s.Type)
+	}
+	if want := []string{"name", "tags", "ingredients", "instructions"}; !slices.Equal(s.Required, want) {
+		t.Errorf("required = %q, want %q", s.Required, want)
+	}
+	for _, tc := range []struct {
+		field string
+		enum  []string
+	}{
+		{"category", sortedKeys(knownCategories)},
+		{"difficulty", []string{"easy", "hard", "medium"}},
+	} {
+		if got := s.Properties[tc.field].Enum; !slices.Equal(got, tc.enum) {
+			t.Errorf("%s enum = %q, want %q", tc.field, got, tc.enum)
+		}
+	}
+	if got := s.Properties["allergens"].Items.Enum; !slices.Contains(got, "peanuts") {
+		t.Errorf("allergen enum = %q, want it to list peanuts", got)
+	}
+	tags := s.Properties["tags"]
+	if tags.Type != "array" || !tags.UniqueItems {
+		t.Errorf("tags = %+v, want an array of unique items", tags)
+	}
+	if !s.Properties["id"].ReadOnly || s.Properties["name"].ReadOnly {
+		t.Error("want id read-only and name writable")
+	}
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultSearchMaxResults caps search results unless SEARCH_MAX_RESULTS
+// says otherwise.
+const defaultSearchMaxResults = 500
+
+// paginateSearch pages search results after capping them at
+// config.SearchMaxResults, flagging the page as truncated when matches
+// were cut.
+func paginateSearch[T any](results []T, page, limit int) Page[T] {
+	total, max := len(results), config.SearchMaxResults
+	if total <= max {
+		return paginate(results, page, limit)
+	}
+	p := paginate(results[:max], page, limit)
+	p.Truncated = true
+	p.Total = total
+	return p
+}
+
+// foldText lower-cases s and strips diacritics so that "Crème" and "creme"
+// compare equal.
+func foldText(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(folded)
+}
+
+// recipeHasTag reports whether r carries tag, ignoring case and accents.
+func recipeHasTag(r Recipe, tag string) bool {
+	tag = foldText(tag)
+	for _, t := range r.Tags {
+		if foldText(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// searchFields are the recipe fields text search can look in, by the name
+// used in ?field=, each returning the strings to search.
+var searchFields = map[string]func(Recipe) []string{
+	"name":         func(r Recipe) []string { return []string{r.Name} },
+	"tags":         func(r Recipe) []string { return r.Tags },
+	"ingredients":  func(r Recipe) []string { return r.Ingredients },
+	"instructions": func(r Recipe) []string { return r.Instructions },
+}
+
+// allSearchFields is every key of searchFields, searched by ?field=all.
+var allSearchFields = []string{"name", "tags", "ingredients", "instructions"}
+
+// defaultSearchFields are searched when no field is given. Instructions are
+// left out so that a word like "salt" finds recipes using it rather than
+// every recipe that mentions seasoning to taste.
+var defaultSearchFields = []string{"name", "tags", "ingredients"}
+
+// parseTextField reads ?field=: one of searchFields, "all" for every one of
+// them, or empty for defaultSearchFields.
+func parseTextField(field string) ([]string, error) {
+	switch field {
+	case "":
+		return defaultSearchFields, nil
+	case "all":
+		return allSearchFields, nil
+	}
+	if _, ok := searchFields[field]; !ok {
+		return nil, errors.New("field must be name, tags, ingredients, instructions or all")
+	}
+	return []string{field}, nil
+}
+
+// recipeMatchesText reports whether the folded query appears in any of the
+// recipe's fields, which default to defaultSearchFields.
+func recipeMatchesText(r Recipe, query string, fields ...string) bool {
+	if len(fields) == 0 {
+		fields = defaultSearchFields
+	}
+	query = foldText(query)
+	for _, field := range fields {
+		for _, text := range searchFields[field](r) {
+			if strings.Contains(foldText(text), query) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recipesIn returns the recipes whose IDs are in ids, in store order.
+// Callers must hold recipesMu.
+func recipesIn(ids idSet) []Recipe {
+	out := make([]Recipe, 0, len(ids))
+	if len(ids) == 0 {
+		return out
+	}
+	for _, r := range recipes {
+		if _, ok := ids[r.ID]; ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// textSearch returns the recipes with query in one of fields, using the
+// index to narrow the candidates before checking each one. Callers must
+// hold recipesMu.
+func textSearch(query string, fields []string) []Recipe {
+	candidates := recipes
+	if ids, ok := searchIndex.textCandidates(query); ok {
+		candidates = recipesIn(ids)
+	}
+	out := make([]Recipe, 0)
+	for _, r := range candidates {
+		if recipeMatchesText(r, query, fields...) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// exprSearchPage returns the requested page of recipes matching expr,
+// sorted by spec. Callers must hold recipesMu.
+func exprSearchPage(expr tagExpr, spec sortSpec, page, limit int) Page[Recipe] {
+	matched := recipesIn(expr.ids(searchIndex))
+	sortRecipes(matched, spec)
+	return paginateSearch(freshList(matched), page, limit)
+}
+
+// textSearchPage returns the requested page of textSearch results, sorted
+// by spec. Callers must hold recipesMu.
+func textSearchPage(query string, fields []string, spec sortSpec, page, limit int) Page[Recipe] {
+	matched := textSearch(query, fields)
+	sortRecipes(matched, spec)
+	return paginateSearch(freshList(matched), page, limit)
+}
+
+// ScoredRecipe is a multi-tag search result with the number of requested
+// tags it carries.
+type ScoredRecipe struct {
+	Recipe
+	MatchCount int `json:"matchCount"`
+}
+
+// multiTagSearch returns the recipes carrying all (or, with matchAny, at
+// least one) of tags. Results are ordered by matchCount descending, then by
+// spec. Callers must hold recipesMu.
+func multiTagSearch(tags []string, matchAny bool, spec sortSpec) []ScoredRecipe {
+	counts := make(map[string]int)
+	for _, tag := range tags {
+		for id := range searchIndex.withTag(tag) {
+			counts[id]++
+		}
+	}
+	matched := make([]Recipe, 0, len(counts))
+	for _, r := range recipes {
+		n := counts[r.ID]
+		if n == 0 || (!matchAny && n < len(tags)) {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	sortRecipes(matched, spec)
+	out := make([]ScoredRecipe, len(matched))
+	for i, r := range matched {
+		out[i] = ScoredRecipe{Recipe: fresh(r), MatchCount: counts[r.ID]}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].MatchCount > out[j].MatchCount })
+	return out
+}
+
+// SearchRecipesHandler returns recipes carrying ?tag=, matching the tag
+// expression in ?q= (see parseTagExpr), carrying the comma-separated ?tags=
+// according to ?match=all|any, or with a faceted tag in ?facet= (and
+// ?value=, if given).
+//
+// @Summary Search recipes by tag
+// @Tags search
+// @Produce json
+// @Param tag query string false "Tag to match"
+// @Param q query string false "Tag expression, e.g. vegan AND (quick OR easy) NOT dessert"
+// @Param tags query string false "Comma-separated tags; results carry a matchCount"
+// @Param match query string false "all (default) or any, for tags"
+// @Param facet query string false "Tag facet, e.g. cuisine for cuisine:italian"
+// @Param value query string false "Facet value; requires facet"
+// @Param sort query string false "name, publishedAt or updatedAt (default publishedAt)"
+// @Param order query string false "asc or desc (default desc)"
+// @Param page query int false "Page number, from 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} PaginatedRecipes
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/search [get]
+func SearchRecipesHandler(c *gin.Context) {
+	if tags := normalizeList(splitList(c.Query("tags"))); len(tags) > 0 {
+		multiTagSearchHandler(c, tags)
+		return
+	}
+
+	var expr tagExpr
+	var query []string
+	mode := "single"
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		parsed, err := parseTagExpr(q)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid q: " + err.Error()})
+			return
+		}
+		expr, mode, query = parsed, "expression", []string{q}
+	} else if tag := strings.TrimSpace(c.Query("tag")); tag != "" {
+		expr, query = tagTerm(tag), []string{"tag", foldText(tag)}
+	} else if facet := strings.TrimSpace(c.Query("facet")); facet != "" {
+		term := newFacetTerm(facet, c.Query("value"))
+		expr, mode, query = term, "facet", []string{term.facet, term.value}
+	} else if c.Query("value") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "value requires facet"})
+		return
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tag, q or facet is required"})
+		return
+	}
+
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	spec, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start := time.Now()
+	results := coalesceSearch(searchKey(mode, spec, page, limit, query...), func() Page[Recipe] {
+		recipesMu.RLock()
+		defer recipesMu.RUnlock()
+		return exprSearchPage(expr, spec, page, limit)
+	})
+	searchDuration.observe(time.Since(start).Seconds(), mode)
+	c.JSON(http.StatusOK, results)
+}
+
+func multiTagSearchHandler(c *gin.Context, tags []string) {
+	var matchAny bool
+	switch c.DefaultQuery("match", "all") {
+	case "all":
+	case "any":
+		matchAny = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "match must be all or any"})
+		return
+	}
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	spec, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start := time.Now()
+	key := searchKey("multi", spec, page, limit, multiTagKey(tags), strconv.FormatBool(matchAny))
+	results := coalesceSearch(key, func() Page[ScoredRecipe] {
+		recipesMu.RLock()
+		defer recipesMu.RUnlock()
+		return paginateSearch(multiTagSearch(tags, matchAny, spec), page, limit)
+	})
+	searchDuration.observe(time.Since(start).Seconds(), "multi")
+	c.JSON(http.StatusOK, results)
+}
+
+// TextSearchRecipesHandler returns recipes whose name, tags or ingredients
+// contain ?q=, ignoring case and accents. ?field= searches only one of those
+// or the instructions instead, or all four with "all". With ?highlight=true
+// each result also carries highlighted snippets of the fields that matched.
+//
+// @Summary Full-text search
+// @Tags search
+// @Produce json
+// @Param q query string true "Text to find in name, tags or ingredients"
+// @Param field query string false "name, tags, ingredients, instructions or all (default name, tags and ingredients)"
+// @Param highlight query bool false "Add highlights with matches wrapped in <mark>"
+// @Param sort query string false "name, publishedAt or updatedAt (default publishedAt)"
+// @Param order query string false "asc or desc (default desc)"
+// @Param page query int false "Page number, from 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} PaginatedRecipes
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/search/text [get]
+func TextSearchRecipesHandler(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	fields, err := parseTextField(c.Query("field"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	spec, err := parseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start := time.Now()
+	key := searchKey("text", spec, page, limit, strings.Join(fields, ","), foldText(query))
+	results := coalesceSearch(key, func() Page[Recipe] {
+		recipesMu.RLock()
+		defer recipesMu.RUnlock()
+		return textSearchPage(query, fields, spec, page, limit)
+	})
+	searchDuration.observe(time.Since(start).Seconds(), "text")
+	if c.Query("highlight") != "true" {
+		c.JSON(http.StatusOK, results)
+		return
+	}
+	highlighted := Page[HighlightedRecipe]{
+		Data:       make([]HighlightedRecipe, len(results.Data)),
+		Pagination: results.Pagination,
+		Truncated:  results.Truncated,
+		Total:      results.Total,
+	}
+	for i, r := range results.Data {
+		highlighted.Data[i] = highlightRecipe(r, query, fields)
+	}
+	c.JSON(http.StatusOK, highlighted)
+}
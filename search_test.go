@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFoldText(t *testing.T) {
+	for in, want := range map[string]string{
+		"Crème Brûlée": "creme brulee",
+		"JALAPEÑO":     "jalapeno",
+		"plain":        "plain",
+	} {
+		if got := foldText(in); got != want {
+			t.Errorf("foldText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTextSearchIgnoresAccents(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("brulee", "Crème brûlée"), testRecipe("creme", "Creme caramel"))
+
+	for q, want := range map[string][]string{
+		"creme":  {"brulee", "creme"},
+		"CRÈME":  {"brulee", "creme"},
+		"brulee": {"brulee"},
+		"caramé": {"creme"},
+	} {
+		got := listIDs(t, router, "/recipes/search/text?sort=name&order=asc&q="+url.QueryEscape(q))
+		if !slices.Equal(got, want) {
+			t.Errorf("q=%s: got %q, want %q", q, got, want)
+		}
+	}
+}
+
+func TestTagSearchIgnoresAccents(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("a", "Dal", "végétarien"), testRecipe("b", "Soup", "vegetarien"))
+
+	for _, tag := range []string{"vegetarien", "Végétarien"} {
+		got := listIDs(t, router, "/recipes/search?sort=name&order=asc&tag="+url.QueryEscape(tag))
+		if want := []string{"a", "b"}; !slices.Equal(got, want) {
+			t.Errorf("tag=%s: got %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func scoredIDs(t *testing.T, router http.Handler, target string) ([]string, []int) {
+	t.Helper()
+	w := serve(router, http.MethodGet, target, "")
+	expectStatus(t, w, http.StatusOK)
+	var ids []string
+	var counts []int
+	for _, r := range decodeBody[Page[ScoredRecipe]](t, w).Data {
+		ids = append(ids, r.ID)
+		counts = append(counts, r.MatchCount)
+	}
+	return ids, counts
+}
+
+func TestMultiTagSearchOrdersByMatchCount(t *testing.T) {
+	router := newTestRouter(t, nil,
+		testRecipe("one", "One", "vegan"),
+		testRecipe("three", "Three", "vegan", "quick", "spicy"),
+		testRecipe("none", "None", "dessert"),
+		testRecipe("two", "Two", "quick", "spicy"),
+	)
+
+	ids, counts := scoredIDs(t, router, "/recipes/search?tags=vegan,quick,spicy&match=any")
+	if !slices.Equal(ids, []string{"three", "two", "one"}) || !slices.Equal(counts, []int{3, 2, 1}) {
+		t.Errorf("match=any: %q with counts %v, want [three two one] with [3 2 1]", ids, counts)
+	}
+	ids, counts = scoredIDs(t, router, "/recipes/search?tags=quick,spicy&sort=name&order=asc")
+	if !slices.Equal(ids, []string{"three", "two"}) || !slices.Equal(counts, []int{2, 2}) {
+		t.Errorf("match=all: %q with counts %v, want [three two] with [2 2]", ids, counts)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/search?tags=quick&match=some", ""), http.StatusBadRequest)
+}
+
+func TestSearchTruncation(t *testing.T) {
+	seed := make([]Recipe, 12)
+	for i := range seed {
+		seed[i] = testRecipe(fmt.Sprintf("r%02d", i), fmt.Sprintf("Tofu bowl %d", i), "vegan")
+	}
+	seed = append(seed, testRecipe("other", "Steak", "meat"))
+	router := newTestRouter(t, func(cfg *Config) { cfg.SearchMaxResults = 5 }, seed...)
+
+	for _, target := range []string{
+		"/recipes/search?tag=vegan",
+		"/recipes/search/text?q=tofu",
+		"/recipes/search?tags=vegan,meat&match=any",
+	} {
+		w := serve(router, http.MethodGet, target+"&limit=2&page=3", "")
+		expectStatus(t, w, http.StatusOK)
+		got := decodeBody[PaginatedRecipes](t, w)
+		if !got.Truncated || got.Total < 12 {
+			t.Errorf("%s: truncated=%v total=%d, want truncated with every match counted", target, got.Truncated, got.Total)
+		}
+		if got.Pagination.Total != 5 || got.Pagination.TotalPages != 3 || got.Pagination.HasNext || len(got.Data) != 1 {
+			t.Errorf("%s: pagination %+v with %d results, want the last of 3 pages over 5 results", target, got.Pagination, len(got.Data))
+		}
+	}
+
+	w := serve(router, http.MethodGet, "/recipes/search?tag=meat", "")
+	expectStatus(t, w, http.StatusOK)
+	if got := decodeBody[map[string]any](t, w); got["truncated"] != nil || got["total"] != nil {
+		t.Errorf("untruncated search returned truncated=%v total=%v", got["truncated"], got["total"])
+	}
+	if got := defaultConfig().SearchMaxResults; got != 500 {
+		t.Errorf("default cap = %d, want 500", got)
+	}
+}
+
+func TestSearchSortOrder(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := []Recipe{
+		testRecipe("b", "Bean Stew", "vegan"),
+		testRecipe("c", "Carrot Soup", "vegan"),
+		testRecipe("a", "Apple Pie", "vegan"),
+	}
+	for i := range seed {
+		seed[i].PublishedAt = Timestamp{base.AddDate(0, 0, i)}
+	}
+	router := newTestRouter(t, nil, seed...)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"a", "c", "b"}},
+		{"&order=asc", []string{"b", "c", "a"}},
+		{"&sort=name", []string{"c", "b", "a"}},
+		{"&sort=name&order=asc", []string{"a", "b", "c"}},
+	} {
+		for _, search := range []string{"tag=vegan", "q=vegan"} {
+			target := "/recipes/search?" + search + tc.query
+			if got := listIDs(t, router, target); !slices.Equal(got, tc.want) {
+				t.Errorf("%s = %q, want %q", target, got, tc.want)
+			}
+		}
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/search?tag=vegan&sort=cost", ""), http.StatusBadRequest)
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/search?tag=vegan&order=up", ""), http.StatusBadRequest)
+}
+
+func TestTextSearchInstructions(t *testing.T) {
+	souffle := testRecipe("souffle", "Cheese Soufflé", "baking")
+	souffle.Instructions = []string{"Whisk the whites.", "Gently FOLD IN the cheese."}
+	salted := testRecipe("salted", "Salted Caramel")
+	salted.Instructions = []string{"Melt the sugar."}
+	router := newTestRouter(t, nil, souffle, salted)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"q=fold+in", []string{}},
+		{"q=fold+in&field=instructions", []string{"souffle"}},
+		{"q=Fold+In&field=all", []string{"souffle"}},
+		{"q=melt&field=instructions", []string{"salted"}},
+		{"q=salted&field=instructions", []string{}},
+		{"q=salted&field=name", []string{"salted"}},
+		{"q=baking&field=tags", []string{"souffle"}},
+		{"q=flour&field=ingredients&sort=name&order=asc", []string{"souffle", "salted"}},
+	} {
+		target := "/recipes/search/text?" + tc.query
+		if got := listIDs(t, router, target); !slices.Equal(got, tc.want) {
+			t.Errorf("%s = %q, want %q", target, got, tc.want)
+		}
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/search/text?q=fold&field=steps", ""), http.StatusBadRequest)
+}
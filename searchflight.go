@@ -0,0 +1,43 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// searchFlight coalesces identical concurrent searches; see coalesceSearch.
+var searchFlight singleflight.Group
+
+// coalesceSearch runs search, sharing one run and its result among
+// concurrent callers with the same key. The key is scoped to the current
+// recipesVersion, so a search that starts after a write never joins a run
+// that may have read the store before it. search must take recipesMu
+// itself, and callers must not modify the shared result.
+func coalesceSearch[T any](key string, search func() Page[T]) Page[T] {
+	key = strconv.FormatUint(recipesVersion.Load(), 10) + "\x00" + key
+	v, _, _ := searchFlight.Do(key, func() (any, error) {
+		return search(), nil
+	})
+	return v.(Page[T])
+}
+
+// searchKey builds a coalescing key from a search mode, its normalized
+// query parts, the sort order and the requested page.
+func searchKey(mode string, spec sortSpec, page, limit int, parts ...string) string {
+	head := []string{mode, spec.field, strconv.FormatBool(spec.desc), strconv.Itoa(page), strconv.Itoa(limit)}
+	return strings.Join(append(head, parts...), "\x00")
+}
+
+// multiTagKey normalizes a multi-tag query for searchKey: tags are matched
+// without regard to case, accents or order.
+func multiTagKey(tags []string) string {
+	folded := make([]string, len(tags))
+	for i, t := range tags {
+		folded[i] = foldText(t)
+	}
+	slices.Sort(folded)
+	return strings.Join(folded, ",")
+}
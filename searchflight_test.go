@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesceSearchRunsOnce(t *testing.T) {
+	newTestRouter(t, nil)
+	const callers = 20
+	var runs atomic.Int32
+	release := make(chan struct{})
+	search := func() Page[Recipe] {
+		runs.Add(1)
+		<-release
+		return paginate([]Recipe{testRecipe("r1", "Soup")}, 1, 10)
+	}
+
+	var ready, done sync.WaitGroup
+	results := make([]Page[Recipe], callers)
+	for i := range callers {
+		ready.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			ready.Done()
+			results[i] = coalesceSearch("same-key", search)
+		}()
+	}
+	ready.Wait()
+	// Give every caller time to join the flight before it finishes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if n := runs.Load(); n != 1 {
+		t.Errorf("search ran %d times for %d identical concurrent calls, want 1", n, callers)
+	}
+	for i, r := range results {
+		if len(r.Data) != 1 || r.Data[0].ID != "r1" {
+			t.Errorf("caller %d got %+v", i, r)
+		}
+	}
+}
+
+func TestCoalesceSearchSeesWrites(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup", "vegan"))
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 10)
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = serve(router, http.MethodGet, "/recipes/search?tag=vegan", "")
+		}()
+	}
+	wg.Wait()
+	for _, w := range responses {
+		expectStatus(t, w, http.StatusOK)
+		if got := decodeBody[PaginatedRecipes](t, w).Data; len(got) != 1 {
+			t.Errorf("concurrent search got %d results, want 1", len(got))
+		}
+	}
+
+	w := serve(router, http.MethodPost, "/recipes", `{"name":"Salad","tags":["vegan"],"ingredients":["lettuce"],"instructions":["Toss."]}`)
+	expectStatus(t, w, http.StatusCreated)
+	if got := listIDs(t, router, "/recipes/search?tag=vegan"); len(got) != 2 {
+		t.Errorf("search after a write = %q, want both vegan recipes", got)
+	}
+
+	// A query differing only in its limit gets its own result.
+	if got := listIDs(t, router, "/recipes/search?tag=vegan&limit=1"); len(got) != 1 {
+		t.Errorf("limit=1 search = %q, want one recipe", got)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServerConfig holds the http.Server tuning options.
+type ServerConfig struct {
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	// MaxQueryBytes caps the raw query string; see MaxQueryMiddleware.
+	// Zero means no limit beyond MaxHeaderBytes.
+	MaxQueryBytes int
+	// H2C serves HTTP/2 without TLS to clients that ask for it, for
+	// deployments behind a proxy that speaks h2c to the backend.
+	H2C bool
+}
+
+// defaultServerConfig leaves room for slow uploads while still closing idle
+// keep-alive connections within two minutes. Its query limit is far beyond
+// any real search but keeps a query from costing more than a few kilobytes
+// of parsing.
+var defaultServerConfig = ServerConfig{
+	ReadTimeout:    15 * time.Second,
+	WriteTimeout:   30 * time.Second,
+	IdleTimeout:    120 * time.Second,
+	MaxHeaderBytes: http.DefaultMaxHeaderBytes,
+	MaxQueryBytes:  8 << 10,
+}
+
+// newServer wraps router in an http.Server listening on port and configured
+// by cfg.
+func newServer(router *gin.Engine, port int, cfg ServerConfig) *http.Server {
+	router.UseH2C = cfg.H2C
+	return &http.Server{
+		Addr:           fmt.Sprintf(":%d", port),
+		Handler:        router.Handler(),
+		ReadTimeout:    cfg.ReadTimeout,
+		WriteTimeout:   cfg.WriteTimeout,
+		IdleTimeout:    cfg.IdleTimeout,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerFromConfig(t *testing.T) {
+	env := map[string]string{
+		"READ_TIMEOUT":     "5s",
+		"WRITE_TIMEOUT":    "1m",
+		"IDLE_TIMEOUT":     "90s",
+		"MAX_HEADER_BYTES": "4096",
+		"ENABLE_H2C":       "true",
+	}
+	cfg, err := loadConfig(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Server.H2C {
+		t.Error("ENABLE_H2C=true did not enable h2c")
+	}
+	srv := newServer(newTestRouter(t, nil), 9000, cfg.Server)
+	if srv.Addr != ":9000" || srv.ReadTimeout != 5*time.Second || srv.WriteTimeout != time.Minute ||
+		srv.IdleTimeout != 90*time.Second || srv.MaxHeaderBytes != 4096 {
+		t.Errorf("server %s read %v write %v idle %v header bytes %d, want the configured values",
+			srv.Addr, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout, srv.MaxHeaderBytes)
+	}
+
+	srv = newServer(newTestRouter(t, nil), 8080, defaultServerConfig)
+	if srv.ReadTimeout == 0 || srv.WriteTimeout == 0 || srv.IdleTimeout == 0 || srv.MaxHeaderBytes != http.DefaultMaxHeaderBytes {
+		t.Errorf("default server %+v, want every timeout set", srv)
+	}
+
+	if _, err := loadConfig(func(k string) string { return map[string]string{"READ_TIMEOUT": "soon"}[k] }); err == nil {
+		t.Error("a bad READ_TIMEOUT was accepted")
+	}
+}
+
+// h2cReply sends the HTTP/2 client preface to a server built with h2c and
+// returns the first bytes of the reply.
+func h2cReply(t *testing.T, h2c bool) []byte {
+	t.Helper()
+	cfg := defaultServerConfig
+	cfg.H2C = h2c
+	ts := httptest.NewServer(newServer(newTestRouter(t, nil), 0, cfg).Handler)
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.WriteString(conn, "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	reply := make([]byte, 9)
+	if _, err := io.ReadFull(bufio.NewReader(conn), reply); err != nil {
+		t.Fatal(err)
+	}
+	return reply
+}
+
+func TestH2C(t *testing.T) {
+	// An HTTP/2 server answers the preface with a SETTINGS frame, type 4.
+	if reply := h2cReply(t, true); reply[3] != 4 {
+		t.Errorf("with h2c, reply %q is not a SETTINGS frame", reply)
+	}
+	if reply := h2cReply(t, false); !strings.HasPrefix(string(reply), "HTTP/1.1") {
+		t.Errorf("without h2c, reply %q is not HTTP/1.1", reply)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScaledShoppingRequest is the body of POST /shopping-list/scaled.
+type ScaledShoppingRequest struct {
+	Items []ScaledShoppingItem `json:"items" binding:"required"`
+}
+
+// ScaledShoppingItem asks for one recipe at a given number of servings.
+type ScaledShoppingItem struct {
+	RecipeID string `json:"recipeId"`
+	Servings int    `json:"servings"`
+}
+
+// ShoppingListEntry is one consolidated line of a shopping list. Quantity and
+// Unit are omitted for lines without a parseable quantity.
+type ShoppingListEntry struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+	Text     string  `json:"text"`
+}
+
+// ShoppingListResponse is a consolidated shopping list plus any notes about
+// recipes that could not be scaled.
+type ShoppingListResponse struct {
+	Items []ShoppingListEntry `json:"items"`
+	Notes []string            `json:"notes,omitempty"`
+}
+
+// shoppingListBuilder sums ingredient quantities sharing a name and unit,
+// keeping first-seen order.
+type shoppingListBuilder struct {
+	entries []ShoppingListEntry
+	index   map[string]int
+}
+
+func newShoppingListBuilder() *shoppingListBuilder {
+	return &shoppingListBuilder{entries: make([]ShoppingListEntry, 0), index: make(map[string]int)}
+}
+
+func (b *shoppingListBuilder) add(line string) {
+	qty, unit, name, ok := parseIngredient(line)
+	if name == "" {
+		return
+	}
+	key := foldText(name) + "|" + unit
+	if !ok {
+		key = foldText(name) + "|-"
+	}
+	if i, seen := b.index[key]; seen {
+		b.entries[i].Quantity += qty
+		return
+	}
+	b.index[key] = len(b.entries)
+	b.entries = append(b.entries, ShoppingListEntry{Name: name, Quantity: qty, Unit: unit})
+}
+
+// list returns the consolidated entries with their display text filled in.
+func (b *shoppingListBuilder) list() []ShoppingListEntry {
+	for i := range b.entries {
+		e := &b.entries[i]
+		parts := make([]string, 0, 3)
+		text, value := formatKitchenQuantity(e.Quantity, e.Unit)
+		if e.Quantity > 0 {
+			parts = append(parts, text)
+		}
+		if e.Unit != "" {
+			parts = append(parts, inflectUnit(e.Unit, value))
+		}
+		e.Text = strings.Join(append(parts, e.Name), " ")
+	}
+	return b.entries
+}
+
+// ScaledShoppingListHandler scales each requested recipe to its servings and
+// consolidates the scaled ingredients into one shopping list.
+//
+// @Summary Build a shopping list from scaled recipes
+// @Tags shopping
+// @Accept json
+// @Produce json
+// @Param request body ScaledShoppingRequest true "Recipes and servings"
+// @Success 200 {object} ShoppingListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /shopping-list/scaled [post]
+func ScaledShoppingListHandler(c *gin.Context) {
+	var req ScaledShoppingRequest
+	if err := bindJSON(c, &req); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	for _, item := range req.Items {
+		if item.Servings <= 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("recipe %s: servings must be a positive integer", item.RecipeID)})
+			return
+		}
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	builder := newShoppingListBuilder()
+	var notes []string
+	for _, item := range req.Items {
+		i := findRecipe(item.RecipeID)
+		if i < 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("recipe %s not found", item.RecipeID)})
+			return
+		}
+		scaled := scaleRecipe(recipes[i], item.Servings)
+		if scaled.Note != "" {
+			notes = append(notes, fmt.Sprintf("%s: %s", recipes[i].Name, scaled.Note))
+		}
+		for _, line := range scaled.Ingredients {
+			builder.add(line)
+		}
+	}
+	c.JSON(http.StatusOK, ShoppingListResponse{Items: builder.list(), Notes: notes})
+}
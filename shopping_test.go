@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestScaledShoppingListAggregates(t *testing.T) {
+	pancakes := testRecipe("pancakes", "Pancakes")
+	pancakes.Servings = 2
+	pancakes.Ingredients = []string{"1 cup flour", "2 eggs", "1 cup milk"}
+	cake := testRecipe("cake", "Cake")
+	cake.Servings = 8
+	cake.Ingredients = []string{"2 cups flour", "4 eggs"}
+	router := newTestRouter(t, nil, pancakes, cake)
+
+	w := serve(router, http.MethodPost, "/shopping-list/scaled",
+		`{"items":[{"recipeId":"pancakes","servings":6},{"recipeId":"cake","servings":4}]}`)
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[ShoppingListResponse](t, w)
+
+	// 3×pancakes + ½×cake: 3+1 cups flour, 6+2 eggs, 3 cups milk.
+	want := map[string]float64{"flour": 4, "eggs": 8, "milk": 3}
+	if len(got.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d lines", got.Items, len(want))
+	}
+	for _, item := range got.Items {
+		if q, ok := want[item.Name]; !ok || item.Quantity != q {
+			t.Errorf("%s: quantity %v, want %v", item.Name, item.Quantity, want[item.Name])
+		}
+	}
+}
+
+func TestScaledShoppingListErrors(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("r1", "Soup"))
+
+	expectStatus(t, serve(router, http.MethodPost, "/shopping-list/scaled",
+		`{"items":[{"recipeId":"missing","servings":2}]}`), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodPost, "/shopping-list/scaled",
+		`{"items":[{"recipeId":"r1","servings":0}]}`), http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSimilarThreshold is the similarity a recipe needs to be listed
+// when ?threshold= is not given: at least half the combined ingredients
+// shared.
+const defaultSimilarThreshold = 0.5
+
+// SimilarRecipe is a recipe with its ingredient similarity to the one asked
+// about.
+type SimilarRecipe struct {
+	Recipe
+	Similarity float64 `json:"similarity"`
+}
+
+// ingredientSet is the set of r's ingredient names, reduced by
+// ingredientName so quantities, units and case do not matter.
+func ingredientSet(r Recipe) map[string]bool {
+	set := make(map[string]bool, len(r.Ingredients))
+	for _, line := range r.Ingredients {
+		if name := ingredientName(line); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// jaccard is the size of the intersection of a and b over the size of their
+// union, 0 when both are empty.
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// similarRecipes returns the recipes other than target whose ingredient
+// similarity to it is at least threshold, most similar first. Callers must
+// hold recipesMu.
+func similarRecipes(target Recipe, threshold float64) []SimilarRecipe {
+	want := ingredientSet(target)
+	out := []SimilarRecipe{}
+	for _, r := range recipes {
+		if r.ID == target.ID {
+			continue
+		}
+		if s := jaccard(want, ingredientSet(r)); s > 0 && s >= threshold {
+			out = append(out, SimilarRecipe{Recipe: fresh(r), Similarity: s})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Similarity > out[j].Similarity })
+	return out
+}
+
+// SimilarRecipesHandler finds recipes sharing most of a recipe's
+// ingredients, whatever their tags, scored by the Jaccard similarity of
+// their ingredient names.
+//
+// @Summary List recipes with similar ingredients
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param threshold query number false "Minimum similarity between 0 and 1 (default 0.5)"
+// @Success 200 {array} SimilarRecipe
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/similar [get]
+func SimilarRecipesHandler(c *gin.Context) {
+	threshold := defaultSimilarThreshold
+	if v := c.Query("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || t > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a number between 0 and 1"})
+			return
+		}
+		threshold = t
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	c.JSON(http.StatusOK, similarRecipes(recipes[i], threshold))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestSimilarRecipes(t *testing.T) {
+	withIngredients := func(id string, ingredients ...string) Recipe {
+		r := testRecipe(id, id)
+		r.Ingredients = ingredients
+		return r
+	}
+	router := newTestRouter(t, nil,
+		withIngredients("pancakes", "200 g flour", "2 eggs", "300 ml milk", "1 tbsp sugar"),
+		withIngredients("crepes", "1 cup Flour", "3 EGGS", "milk", "sugar"),
+		withIngredients("yorkshire", "flour", "eggs", "milk", "butter"),
+		withIngredients("bread", "flour", "eggs", "yeast", "water"),
+		withIngredients("salad", "lettuce"),
+	)
+
+	type score struct {
+		id         string
+		similarity float64
+	}
+	for _, tc := range []struct {
+		query string
+		want  []score
+	}{
+		{"", []score{{"crepes", 1}, {"yorkshire", 0.6}}},
+		{"?threshold=0.3", []score{{"crepes", 1}, {"yorkshire", 0.6}, {"bread", 2.0 / 6}}},
+		{"?threshold=0", []score{{"crepes", 1}, {"yorkshire", 0.6}, {"bread", 2.0 / 6}}},
+		{"?threshold=1", []score{{"crepes", 1}}},
+	} {
+		w := serve(router, http.MethodGet, "/recipe/pancakes/similar"+tc.query, "")
+		expectStatus(t, w, http.StatusOK)
+		got := decodeBody[[]SimilarRecipe](t, w)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %d recipes, want %v", tc.query, len(got), tc.want)
+			continue
+		}
+		for i, s := range got {
+			if s.ID != tc.want[i].id || math.Abs(s.Similarity-tc.want[i].similarity) > 1e-9 {
+				t.Errorf("%s: result %d = %s %.3f, want %s %.3f", tc.query, i, s.ID, s.Similarity, tc.want[i].id, tc.want[i].similarity)
+			}
+		}
+	}
+
+	for _, q := range []string{"-0.1", "1.5", "most"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipe/pancakes/similar?threshold="+q, ""), http.StatusBadRequest)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/similar", ""), http.StatusNotFound)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/collate"
+)
+
+// sortSpec is a field to order recipes by and its direction.
+type sortSpec struct {
+	field string
+	desc  bool
+}
+
+// sortableFields are the values accepted in ?sort=.
+var sortableFields = map[string]bool{
+	"name":        true,
+	"publishedAt": true,
+	"updatedAt":   true,
+}
+
+// parseSort reads ?sort= and ?order=, filling in config.DefaultSort for
+// anything missing.
+func parseSort(c *gin.Context) (sortSpec, error) {
+	return newSortSpec(c.Query("sort"), c.Query("order"))
+}
+
+// newSortSpec validates a sort field and order, either of which may be
+// empty to use config.DefaultSort's.
+func newSortSpec(field, order string) (sortSpec, error) {
+	spec := config.DefaultSort
+	if field != "" {
+		if !sortableFields[field] {
+			return sortSpec{}, fmt.Errorf("sort must be one of name, publishedAt, updatedAt")
+		}
+		spec.field = field
+	}
+	switch order {
+	case "":
+	case "asc":
+		spec.desc = false
+	case "desc":
+		spec.desc = true
+	default:
+		return sortSpec{}, fmt.Errorf("order must be asc or desc")
+	}
+	return spec, nil
+}
+
+// pinnedFirst returns list with its pinned recipes first, in their existing
+// order, followed by the rest sorted by spec.
+func pinnedFirst(list []Recipe, spec sortSpec) []Recipe {
+	out := make([]Recipe, 0, len(list))
+	var rest []Recipe
+	for _, r := range list {
+		if r.Pinned {
+			out = append(out, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	sortRecipes(rest, spec)
+	return append(out, rest...)
+}
+
+// sortRecipes orders list in place. Names compare by config.SortLocale's
+// collation so accented letters sort next to their base letter; ties fall
+// back to ID so the order is deterministic across requests.
+func sortRecipes(list []Recipe, spec sortSpec) {
+	var cmp func(a, b *Recipe) int
+	switch spec.field {
+	case "name":
+		col := collate.New(config.SortLocale, collate.IgnoreCase)
+		cmp = func(a, b *Recipe) int { return col.CompareString(a.Name, b.Name) }
+	case "updatedAt":
+		cmp = func(a, b *Recipe) int { return lastModified(*a).Compare(lastModified(*b)) }
+	default:
+		cmp = func(a, b *Recipe) int { return a.PublishedAt.Compare(b.PublishedAt.Time) }
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		n := cmp(&list[i], &list[j])
+		if n == 0 {
+			return list[i].ID < list[j].ID
+		}
+		if spec.desc {
+			return n > 0
+		}
+		return n < 0
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestNameSortUsesCollation(t *testing.T) {
+	seed := []Recipe{
+		testRecipe("zucchini", "Zucchini fritters"),
+		testRecipe("eclair", "Éclair"),
+		testRecipe("eggs", "eggs benedict"),
+		testRecipe("apple", "Apple pie"),
+	}
+	router := newTestRouter(t, nil, seed...)
+
+	names := make([]string, len(seed))
+	for i, r := range seed {
+		names[i] = r.Name
+	}
+	sort.Strings(names)
+	if names[len(names)-1] != "Éclair" {
+		t.Fatalf("byte order = %q; expected Éclair last", names)
+	}
+
+	got := listIDs(t, router, "/recipes?sort=name&order=asc")
+	if want := []string{"apple", "eclair", "eggs", "zucchini"}; !slices.Equal(got, want) {
+		t.Errorf("name order = %q, want %q", got, want)
+	}
+}
+
+func TestNameSortLocale(t *testing.T) {
+	seed := []Recipe{testRecipe("ol", "Öl"), testRecipe("zebra", "Zebra cake")}
+	for locale, want := range map[language.Tag][]string{
+		language.English: {"ol", "zebra"},
+		language.Swedish: {"zebra", "ol"},
+	} {
+		router := newTestRouter(t, func(cfg *Config) { cfg.SortLocale = locale }, seed...)
+		if got := listIDs(t, router, "/recipes?sort=name&order=asc"); !slices.Equal(got, want) {
+			t.Errorf("%s: order = %q, want %q", locale, got, want)
+		}
+	}
+}
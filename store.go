@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	recipesMu sync.RWMutex
+	recipes   = make([]Recipe, 0)
+
+	// tombstones records deleted recipes for the changes feed, oldest first.
+	// tombstoneHorizon is the deletion time of the newest tombstone pruned
+	// from it: a client that last synced before then may have missed a
+	// deletion. Both are guarded by recipesMu.
+	tombstones       []Tombstone
+	tombstoneHorizon time.Time
+
+	// listCache holds the marshaled response of an unfiltered GET /recipes.
+	// It is guarded by recipesMu, reset by recipesChanged and rebuilt lazily.
+	// listCacheExpires is when the freshness of a cached recipe next changes;
+	// zero means never.
+	listCache        []byte
+	listCacheExpires time.Time
+
+	// changeSignal is closed and replaced by recipesChanged, waking every
+	// long poll waiting on it. It is guarded by recipesMu.
+	changeSignal = make(chan struct{})
+
+	// recipesVersion is incremented by recipesChanged. It can be read
+	// without recipesMu, e.g. to key cached or coalesced results.
+	recipesVersion atomic.Uint64
+)
+
+// Tombstone marks a recipe that has been deleted.
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// Tombstones are kept for tombstoneRetention, and at most maxTombstones of
+// them, so that deletes, evictions and collection replacements cannot grow
+// memory without bound.
+const (
+	tombstoneRetention = 30 * 24 * time.Hour
+	maxTombstones      = 10000
+)
+
+// addTombstone records the deletion of id at at, pruning tombstones that
+// have outlived tombstoneRetention or exceed maxTombstones and moving
+// tombstoneHorizon up to the newest one pruned. Callers must hold recipesMu
+// for writing.
+func addTombstone(id string, at time.Time) {
+	tombstones = append(tombstones, Tombstone{ID: id, DeletedAt: at})
+	drop := max(0, len(tombstones)-maxTombstones)
+	for drop < len(tombstones) && at.Sub(tombstones[drop].DeletedAt) > tombstoneRetention {
+		drop++
+	}
+	if drop > 0 {
+		tombstoneHorizon = tombstones[drop-1].DeletedAt
+		tombstones = tombstones[drop:]
+	}
+}
+
+// recipesChanged must be called after every mutation of recipes, with
+// recipesMu held for writing. Prefer the insert/replace/remove helpers, which
+// call it and keep the search index in sync.
+func recipesChanged() {
+	listCache = nil
+	close(changeSignal)
+	changeSignal = make(chan struct{})
+	recipesVersion.Add(1)
+}
+
+// insertRecipe appends r. Callers must hold recipesMu for writing.
+func insertRecipe(r Recipe) {
+	recipes = append(recipes, r)
+	searchIndex.add(r)
+	recipesChanged()
+}
+
+// replaceRecipe overwrites the recipe at index i. Callers must hold recipesMu
+// for writing.
+func replaceRecipe(i int, r Recipe) {
+	searchIndex.remove(recipes[i])
+	recipes[i] = r
+	searchIndex.add(r)
+	recipesChanged()
+}
+
+// replaceAllRecipes swaps in list as the whole store, leaving tombstones for
+// recipes that are not in list. A recipe without an ID leaves none. Callers
+// must hold recipesMu for writing.
+func replaceAllRecipes(list []Recipe) {
+	kept := make(map[string]bool, len(list))
+	for _, r := range list {
+		kept[r.ID] = true
+	}
+	now := time.Now()
+	for _, r := range recipes {
+		if r.ID != "" && !kept[r.ID] {
+			addTombstone(r.ID, now)
+		}
+	}
+	recipes = list
+	searchIndex.rebuild(recipes)
+	recipesChanged()
+}
+
+// removeRecipe deletes the recipe at index i, leaves a tombstone for it and
+// returns it. The rest keep their relative order, which pinned recipes and
+// store-order results such as search rely on, and the vacated tail slot is
+// cleared so the removed recipe is not retained. Its forks are orphaned
+// rather than removed with it. Callers must hold recipesMu
+// for writing.
+func removeRecipe(i int) Recipe {
+	removed := recipes[i]
+	now := time.Now()
+	recipes = slices.Delete(recipes, i, i+1)
+	addTombstone(removed.ID, now)
+	orphanForks(removed.ID, now)
+	searchIndex.remove(removed)
+	recipesChanged()
+	return removed
+}
+
+// loadRecipes replaces the in-memory store with the recipes in path. A
+// missing file is not an error; the store simply starts empty. The data is
+// checked for integrity problems first; see checkIntegrity.
+func loadRecipes(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	loaded := make([]Recipe, 0)
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return err
+	}
+	if err := reportIntegrity(path, checkIntegrity(loaded)); err != nil {
+		return err
+	}
+	recipesMu.Lock()
+	replaceAllRecipes(loaded)
+	recipesMu.Unlock()
+	return nil
+}
+
+// findRecipe returns the index of the recipe with the given ID, or -1.
+// Callers must hold recipesMu.
+func findRecipe(id string) int {
+	for i := range recipes {
+		if recipes[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// capacityPolicy bounds the number of stored recipes. A zero max means no
+// limit. When full, new recipes are rejected unless evictOldest is set, in
+// which case the recipe with the earliest PublishedAt makes way.
+type capacityPolicy struct {
+	max         int
+	evictOldest bool
+}
+
+// makeRoom ensures there is space for one more recipe, evicting if the
+// policy allows, and returns the ID of any evicted recipe. It reports false
+// when the store is full and the recipe must be rejected. Callers must hold
+// recipesMu for writing.
+func (p capacityPolicy) makeRoom() (evicted string, ok bool) {
+	if p.max == 0 || len(recipes) < p.max {
+		return "", true
+	}
+	if !p.evictOldest || len(recipes) == 0 {
+		return "", false
+	}
+	oldest := 0
+	for i := range recipes {
+		if recipes[i].PublishedAt.Before(recipes[oldest].PublishedAt.Time) {
+			oldest = i
+		}
+	}
+	evicted = removeRecipe(oldest).ID
+	log.Printf("recipe store full, evicting %s", evicted)
+	return evicted, true
+}
+
+// fits reports whether n more recipes can be stored, counting evictions the
+// policy allows. Callers must hold recipesMu.
+func (p capacityPolicy) fits(n int) bool {
+	return p.max == 0 || p.evictOldest || len(recipes)+n <= p.max
+}
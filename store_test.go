@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+const newRecipeBody = `{"name":"Toast","ingredients":["bread"],"instructions":["Toast the bread."]}`
+
+func cappedStore(t *testing.T, evictOldest bool) http.Handler {
+	t.Helper()
+	older := testRecipe("older", "Older")
+	older.PublishedAt = Timestamp{time.Now().Add(-2 * time.Hour)}
+	newer := testRecipe("newer", "Newer")
+	newer.PublishedAt = Timestamp{time.Now().Add(-time.Hour)}
+	return newTestRouter(t, func(cfg *Config) {
+		cfg.Capacity = capacityPolicy{max: 2, evictOldest: evictOldest}
+	}, newer, older)
+}
+
+func TestCapacityRejects(t *testing.T) {
+	router := cappedStore(t, false)
+
+	expectStatus(t, serve(router, http.MethodPost, "/recipes", newRecipeBody), http.StatusInsufficientStorage)
+	if n := len(recipes); n != 2 {
+		t.Errorf("stored %d recipes, want 2", n)
+	}
+}
+
+func TestCapacityEvictsOldest(t *testing.T) {
+	router := cappedStore(t, true)
+
+	w := serve(router, http.MethodPost, "/recipes", newRecipeBody)
+	expectStatus(t, w, http.StatusCreated)
+	storedRecipe(t, decodeBody[Recipe](t, w).ID)
+	storedRecipe(t, "newer")
+	if findRecipe("older") >= 0 {
+		t.Error("oldest recipe was not evicted")
+	}
+	if n := len(recipes); n != 2 {
+		t.Errorf("stored %d recipes, want 2", n)
+	}
+}
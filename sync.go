@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// lastModified returns when r last changed. Recipes loaded without an
+// UpdatedAt fall back to their publication time.
+func lastModified(r Recipe) time.Time {
+	if r.UpdatedAt.IsZero() {
+		return r.PublishedAt.Time
+	}
+	return r.UpdatedAt.Time
+}
+
+// parseSince reads the optional RFC 3339 ?since= parameter.
+func parseSince(c *gin.Context) (since time.Time, ok bool, err error) {
+	v := c.Query("since")
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+	since, err = time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return since, true, nil
+}
+
+// RecipeStamp is the ID and modification time of a recipe, used by sync
+// clients to decide what to fetch.
+type RecipeStamp struct {
+	ID        string    `json:"id"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RecipeIDsHandler lists every recipe ID with its modification time,
+// optionally only those changed after ?since=.
+//
+// @Summary List recipe IDs for sync
+// @Tags sync
+// @Produce json
+// @Param since query string false "RFC 3339 timestamp; only recipes changed after it"
+// @Success 200 {array} RecipeStamp
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/ids [get]
+func RecipeIDsHandler(c *gin.Context) {
+	since, filtered, err := parseSince(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 timestamp"})
+		return
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	out := make([]RecipeStamp, 0, len(recipes))
+	for _, r := range recipes {
+		modified := lastModified(r)
+		if filtered && !modified.After(since) {
+			continue
+		}
+		out = append(out, RecipeStamp{ID: r.ID, UpdatedAt: modified})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Change is one entry of the changes feed: either a created or updated
+// recipe, or a tombstone for a deleted one.
+type Change struct {
+	ID        string    `json:"id"`
+	Deleted   bool      `json:"deleted"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Recipe    *Recipe   `json:"recipe,omitempty"`
+}
+
+// errResyncRequired answers a ?since= older than tombstoneHorizon, when
+// deletions the client has not seen may already have been pruned.
+const errResyncRequired = "since is older than the deletions still recorded; resync by fetching /recipes/changes without since"
+
+// sinceExpired reports whether since predates tombstoneHorizon, so that a
+// feed after it could silently miss deletions. Callers must hold recipesMu.
+func sinceExpired(since time.Time) bool {
+	return since.Before(tombstoneHorizon)
+}
+
+// changesSince collects the changes after since (all of them when filtered
+// is false), oldest first. Callers must hold recipesMu.
+func changesSince(since time.Time, filtered bool) []Change {
+	out := make([]Change, 0)
+	for i := range recipes {
+		modified := lastModified(recipes[i])
+		if filtered && !modified.After(since) {
+			continue
+		}
+		r := fresh(recipes[i])
+		out = append(out, Change{ID: r.ID, UpdatedAt: modified, Recipe: &r})
+	}
+	for _, t := range tombstones {
+		if filtered && !t.DeletedAt.After(since) {
+			continue
+		}
+		out = append(out, Change{ID: t.ID, Deleted: true, UpdatedAt: t.DeletedAt})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].UpdatedAt.Before(out[j].UpdatedAt) })
+	return out
+}
+
+// RecipeChangesHandler returns recipes created or updated after ?since=,
+// plus tombstones for recipes deleted since then, ordered by time ascending.
+// Tombstones are only kept for a while (see addTombstone); a since from
+// before the oldest deletion still recorded gets 410 and the client must
+// resync from the full feed.
+//
+// @Summary Changes feed
+// @Tags sync
+// @Produce json
+// @Param since query string false "RFC 3339 timestamp; only changes after it"
+// @Success 200 {array} Change
+// @Failure 400 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse "since is older than the tombstones kept; resync without since"
+// @Router /recipes/changes [get]
+func RecipeChangesHandler(c *gin.Context) {
+	since, filtered, err := parseSince(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 timestamp"})
+		return
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	if filtered && sinceExpired(since) {
+		c.JSON(http.StatusGone, gin.H{"error": errResyncRequired})
+		return
+	}
+	c.JSON(http.StatusOK, changesSince(since, filtered))
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func stampIDs(t *testing.T, router http.Handler, target string) []string {
+	t.Helper()
+	w := serve(router, http.MethodGet, target, "")
+	expectStatus(t, w, http.StatusOK)
+	ids := make([]string, 0)
+	for _, s := range decodeBody[[]RecipeStamp](t, w) {
+		if s.UpdatedAt.IsZero() {
+			t.Errorf("%s has no updatedAt", s.ID)
+		}
+		ids = append(ids, s.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestRecipeIDs(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := testRecipe("old", "Old")
+	old.PublishedAt = Timestamp{base}
+	edited := testRecipe("edited", "Edited")
+	edited.PublishedAt = Timestamp{base}
+	edited.UpdatedAt = Timestamp{base.Add(48 * time.Hour)}
+	recent := testRecipe("recent", "Recent")
+	recent.PublishedAt = Timestamp{base.Add(72 * time.Hour)}
+	router := newTestRouter(t, nil, old, edited, recent)
+
+	if got, want := stampIDs(t, router, "/recipes/ids"), []string{"edited", "old", "recent"}; !slices.Equal(got, want) {
+		t.Errorf("ids = %q, want %q", got, want)
+	}
+	since := url.QueryEscape(base.Add(24 * time.Hour).Format(time.RFC3339))
+	if got, want := stampIDs(t, router, "/recipes/ids?since="+since), []string{"edited", "recent"}; !slices.Equal(got, want) {
+		t.Errorf("ids since a day in = %q, want %q", got, want)
+	}
+	since = url.QueryEscape(base.Add(72 * time.Hour).Format(time.RFC3339))
+	if got := stampIDs(t, router, "/recipes/ids?since="+since); len(got) != 0 {
+		t.Errorf("ids since the last change = %q, want none", got)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/ids?since=yesterday", ""), http.StatusBadRequest)
+}
+
+func TestChangesFeed(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := make([]Recipe, 3)
+	for i, id := range []string{"untouched", "edited", "gone"} {
+		seed[i] = testRecipe(id, id)
+		seed[i].PublishedAt = Timestamp{base}
+	}
+	router := newTestRouter(t, nil, seed...)
+
+	w := serve(router, http.MethodPost, "/recipes", newRecipeBody)
+	expectStatus(t, w, http.StatusCreated)
+	created := decodeBody[Recipe](t, w).ID
+	expectStatus(t, serve(router, http.MethodPut, "/recipe/edited",
+		`{"name":"Edited","ingredients":["rice"],"instructions":["Cook."]}`), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/gone", ""), http.StatusOK)
+
+	since := url.QueryEscape(base.Add(time.Hour).Format(time.RFC3339))
+	w = serve(router, http.MethodGet, "/recipes/changes?since="+since, "")
+	expectStatus(t, w, http.StatusOK)
+	changes := decodeBody[[]Change](t, w)
+	if len(changes) != 3 {
+		t.Fatalf("changes = %+v, want created, updated and deleted", changes)
+	}
+	for i, want := range []struct {
+		id      string
+		deleted bool
+	}{{created, false}, {"edited", false}, {"gone", true}} {
+		c := changes[i]
+		if c.ID != want.id || c.Deleted != want.deleted || (c.Recipe == nil) != want.deleted {
+			t.Errorf("change %d = %+v, want id %s deleted %v", i, c, want.id, want.deleted)
+		}
+		if i > 0 && c.UpdatedAt.Before(changes[i-1].UpdatedAt) {
+			t.Errorf("change %d is older than change %d", i, i-1)
+		}
+	}
+	if changes[1].Recipe != nil && changes[1].Recipe.Name != "Edited" {
+		t.Errorf("updated change carries %+v, want the new version", changes[1].Recipe)
+	}
+
+	w = serve(router, http.MethodGet, "/recipes/changes", "")
+	expectStatus(t, w, http.StatusOK)
+	if all := decodeBody[[]Change](t, w); len(all) != 4 || all[0].ID != "untouched" {
+		t.Errorf("unfiltered feed = %+v, want untouched first and 4 changes", all)
+	}
+}
+
+func TestTombstonesArePruned(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("gone", "Gone"))
+	now := time.Now()
+	expired := now.Add(-tombstoneRetention - time.Hour)
+	recipesMu.Lock()
+	addTombstone("expired", expired)
+	recipesMu.Unlock()
+
+	expectStatus(t, serve(router, http.MethodDelete, "/recipe/gone", ""), http.StatusOK)
+	recipesMu.RLock()
+	kept, horizon := slices.Clone(tombstones), tombstoneHorizon
+	recipesMu.RUnlock()
+	if len(kept) != 1 || kept[0].ID != "gone" || !horizon.Equal(expired) {
+		t.Fatalf("tombstones = %+v with horizon %v, want only gone with horizon %v", kept, horizon, expired)
+	}
+
+	before := url.QueryEscape(expired.Add(-time.Second).Format(time.RFC3339Nano))
+	for _, target := range []string{"/recipes/changes?since=" + before, "/recipes/poll?timeout=0s&since=" + before} {
+		w := serve(router, http.MethodGet, target, "")
+		expectStatus(t, w, http.StatusGone)
+		if got := decodeBody[ErrorResponse](t, w).Error; got != errResyncRequired {
+			t.Errorf("%s: error = %q, want %q", target, got, errResyncRequired)
+		}
+	}
+	at := url.QueryEscape(expired.Format(time.RFC3339Nano))
+	w := serve(router, http.MethodGet, "/recipes/changes?since="+at, "")
+	expectStatus(t, w, http.StatusOK)
+	if changes := decodeBody[[]Change](t, w); len(changes) != 1 || changes[0].ID != "gone" || !changes[0].Deleted {
+		t.Errorf("changes since the horizon = %+v, want the tombstone for gone", changes)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipes/changes", ""), http.StatusOK)
+}
+
+func TestTombstonesAreCapped(t *testing.T) {
+	newTestRouter(t, nil)
+	start := time.Now()
+	recipesMu.Lock()
+	defer recipesMu.Unlock()
+	for i := range maxTombstones + 5 {
+		addTombstone("r"+strconv.Itoa(i), start.Add(time.Duration(i)*time.Millisecond))
+	}
+	if len(tombstones) != maxTombstones || tombstones[0].ID != "r5" {
+		t.Errorf("kept %d tombstones from %s, want %d from r5", len(tombstones), tombstones[0].ID, maxTombstones)
+	}
+	if want := start.Add(4 * time.Millisecond); !tombstoneHorizon.Equal(want) {
+		t.Errorf("horizon = %v, want the deletion of r4 at %v", tombstoneHorizon, want)
+	}
+}
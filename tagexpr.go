@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tagExpr is a boolean expression over recipe tags, e.g.
+// "vegan AND (quick OR easy) NOT dessert". Adjacent terms without an operator
+// are joined with AND. eval tests one recipe; ids answers the expression from
+// the search index.
+type tagExpr interface {
+	eval(r Recipe) bool
+	ids(idx *recipeIndex) idSet
+}
+
+type tagTerm string
+
+func (t tagTerm) eval(r Recipe) bool { return recipeHasTag(r, string(t)) }
+
+func (t tagTerm) ids(idx *recipeIndex) idSet { return idx.withTag(string(t)) }
+
+type notExpr struct{ x tagExpr }
+
+func (n notExpr) eval(r Recipe) bool { return !n.x.eval(r) }
+
+func (n notExpr) ids(idx *recipeIndex) idSet {
+	exclude := n.x.ids(idx)
+	out := make(idSet, len(idx.all))
+	for id := range idx.all {
+		if _, ok := exclude[id]; !ok {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
+
+type andExpr struct{ l, r tagExpr }
+
+func (a andExpr) eval(r Recipe) bool { return a.l.eval(r) && a.r.eval(r) }
+
+func (a andExpr) ids(idx *recipeIndex) idSet {
+	left, right := a.l.ids(idx), a.r.ids(idx)
+	if len(right) < len(left) {
+		left, right = right, left
+	}
+	out := make(idSet)
+	for id := range left {
+		if _, ok := right[id]; ok {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
+
+type orExpr struct{ l, r tagExpr }
+
+func (o orExpr) eval(r Recipe) bool { return o.l.eval(r) || o.r.eval(r) }
+
+func (o orExpr) ids(idx *recipeIndex) idSet {
+	out := make(idSet)
+	for id := range o.l.ids(idx) {
+		out[id] = struct{}{}
+	}
+	for id := range o.r.ids(idx) {
+		out[id] = struct{}{}
+	}
+	return out
+}
+
+type exprToken struct {
+	text string
+	pos  int
+}
+
+// tokenizeTagExpr splits s into parentheses and whitespace-separated words,
+// recording each token's 1-based byte offset for error messages.
+func tokenizeTagExpr(s string) []exprToken {
+	var tokens []exprToken
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, exprToken{text: s[start:end], pos: start + 1})
+			start = -1
+		}
+	}
+	for i, ch := range s {
+		switch {
+		case ch == '(' || ch == ')':
+			flush(i)
+			tokens = append(tokens, exprToken{text: string(ch), pos: i + 1})
+		case unicode.IsSpace(ch):
+			flush(i)
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	flush(len(s))
+	return tokens
+}
+
+// Tag expressions are parsed and evaluated recursively, so their size and
+// nesting are bounded to keep a hostile query from exhausting the stack.
+const (
+	maxTagExprTokens = 256
+	maxTagExprDepth  = 32
+)
+
+type tagExprParser struct {
+	tokens []exprToken
+	next   int
+	depth  int
+}
+
+// parseTagExpr parses a tag expression. Operators AND, OR and NOT are
+// case-insensitive; NOT binds tighter than AND, which binds tighter than OR.
+func parseTagExpr(s string) (tagExpr, error) {
+	p := &tagExprParser{tokens: tokenizeTagExpr(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	if len(p.tokens) > maxTagExprTokens {
+		return nil, fmt.Errorf("expression has more than %d terms, operators and parentheses", maxTagExprTokens)
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return expr, nil
+}
+
+func (p *tagExprParser) peek() (exprToken, bool) {
+	if p.next >= len(p.tokens) {
+		return exprToken{}, false
+	}
+	return p.tokens[p.next], true
+}
+
+// enter descends into a NOT or parenthesis at tok, failing once the nesting
+// passes maxTagExprDepth. Each successful enter must be paired with leave.
+func (p *tagExprParser) enter(tok exprToken) error {
+	if p.depth >= maxTagExprDepth {
+		return fmt.Errorf("expression nests deeper than %d at position %d", maxTagExprDepth, tok.pos)
+	}
+	p.depth++
+	return nil
+}
+
+func (p *tagExprParser) leave() { p.depth-- }
+
+func (p *tagExprParser) peekKeyword(kw string) bool {
+	tok, ok := p.peek()
+	return ok && strings.EqualFold(tok.text, kw)
+}
+
+func (p *tagExprParser) parseOr() (tagExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("OR") {
+		p.next++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagExprParser) parseAnd() (tagExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.text == ")" || strings.EqualFold(tok.text, "OR") {
+			return left, nil
+		}
+		if strings.EqualFold(tok.text, "AND") {
+			p.next++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *tagExprParser) parseNot() (tagExpr, error) {
+	if p.peekKeyword("NOT") {
+		tok, _ := p.peek()
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		p.next++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *tagExprParser) parsePrimary() (tagExpr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	switch {
+	case tok.text == "(":
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		p.next++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.text != ")" {
+			return nil, fmt.Errorf("missing ')' for '(' at position %d", tok.pos)
+		}
+		p.next++
+		return inner, nil
+	case tok.text == ")", strings.EqualFold(tok.text, "AND"), strings.EqualFold(tok.text, "OR"):
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	p.next++
+	return tagTerm(tok.text), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTagExpressionSearch(t *testing.T) {
+	router := newTestRouter(t, nil,
+		testRecipe("curry", "Curry", "vegan", "quick"),
+		testRecipe("sorbet", "Sorbet", "vegan", "easy", "dessert"),
+		testRecipe("salad", "Salad", "vegan", "easy"),
+		testRecipe("stew", "Stew", "vegan"),
+		testRecipe("steak", "Steak", "quick"),
+	)
+
+	for q, want := range map[string][]string{
+		"vegan AND quick":                           {"curry"},
+		"quick OR easy":                             {"curry", "salad", "sorbet", "steak"},
+		"vegan NOT dessert":                         {"curry", "salad", "stew"},
+		"NOT vegan":                                 {"steak"},
+		"vegan AND (quick OR easy) NOT dessert":     {"curry", "salad"},
+		"(vegan AND easy) OR (quick AND NOT vegan)": {"salad", "sorbet", "steak"},
+	} {
+		got := listIDs(t, router, "/recipes/search?sort=name&order=asc&q="+url.QueryEscape(q))
+		if !slices.Equal(got, want) {
+			t.Errorf("q=%q: got %q, want %q", q, got, want)
+		}
+	}
+}
+
+func TestTagExpressionMalformed(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("curry", "Curry", "vegan"))
+
+	for _, q := range []string{"vegan AND", "(vegan OR quick", "vegan )", "AND quick", "NOT"} {
+		w := serve(router, http.MethodGet, "/recipes/search?q="+url.QueryEscape(q), "")
+		expectStatus(t, w, http.StatusBadRequest)
+		if msg := decodeBody[ErrorResponse](t, w).Error; !strings.HasPrefix(msg, "invalid q: ") {
+			t.Errorf("q=%q: error %q does not describe the parse error", q, msg)
+		}
+	}
+}
+
+func TestTagExpressionLimits(t *testing.T) {
+	nested := func(open, term, close string, n int) string {
+		return strings.Repeat(open, n) + term + strings.Repeat(close, n)
+	}
+	for _, q := range []string{
+		nested("(", "vegan", ")", maxTagExprDepth),
+		nested("NOT ", "vegan", "", maxTagExprDepth),
+		strings.Repeat("vegan AND ", maxTagExprTokens/2-1) + "vegan vegan",
+	} {
+		if _, err := parseTagExpr(q); err != nil {
+			t.Errorf("parseTagExpr(%.40q...) = %v, want it accepted", q, err)
+		}
+	}
+	for _, tc := range []struct{ q, wantErr string }{
+		{nested("(", "vegan", ")", maxTagExprDepth+1), "nests deeper than 32"},
+		{nested("NOT ", "vegan", "", maxTagExprDepth+1), "nests deeper than 32"},
+		{"(NOT " + nested("(", "vegan", ")", maxTagExprDepth), "nests deeper than 32"},
+		{strings.Repeat("vegan ", maxTagExprTokens+1), "more than 256"},
+	} {
+		if _, err := parseTagExpr(tc.q); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("parseTagExpr(%.40q...) = %v, want an error containing %q", tc.q, err, tc.wantErr)
+		}
+	}
+
+	router := newTestRouter(t, nil, testRecipe("curry", "Curry", "vegan"))
+	deep := nested("(", "vegan", ")", 1000)
+	w := serve(router, http.MethodPost, "/recipes/multi-search", `{"queries":[{"q":"`+deep+`"}]}`)
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := decodeBody[ErrorResponse](t, w).Error; !strings.HasPrefix(got, "query 0: invalid q: expression has more than") {
+		t.Errorf("error = %q, want the expression rejected", got)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timer is a duration found in one instruction step. Step is 1-based and
+// Text is the phrase the duration was read from.
+type Timer struct {
+	Step            int    `json:"step"`
+	DurationSeconds int    `json:"durationSeconds"`
+	Text            string `json:"text"`
+}
+
+var durationPattern = regexp.MustCompile(`(?i)\b(\d+(?:\.\d+)?)\s*(hours?|hrs?|minutes?|mins?|seconds?|secs?)\b`)
+
+// unitSeconds converts a matched duration unit to seconds.
+func unitSeconds(unit string) float64 {
+	switch u := strings.ToLower(unit); {
+	case strings.HasPrefix(u, "h"):
+		return 3600
+	case strings.HasPrefix(u, "m"):
+		return 60
+	default:
+		return 1
+	}
+}
+
+// extractTimers finds durations such as "25 minutes" or "1 hour 15 minutes"
+// in a step. Durations separated only by spaces or "and" are combined into a
+// single timer.
+func extractTimers(step int, text string) []Timer {
+	var timers []Timer
+	start, end := 0, -1
+	for _, m := range durationPattern.FindAllStringSubmatchIndex(text, -1) {
+		n, _ := strconv.ParseFloat(text[m[2]:m[3]], 64)
+		seconds := int(math.Round(n * unitSeconds(text[m[4]:m[5]])))
+		if end >= 0 {
+			if gap := strings.TrimSpace(text[end:m[0]]); gap == "" || strings.EqualFold(gap, "and") {
+				last := &timers[len(timers)-1]
+				last.DurationSeconds += seconds
+				last.Text = text[start:m[1]]
+				end = m[1]
+				continue
+			}
+		}
+		start, end = m[0], m[1]
+		timers = append(timers, Timer{Step: step, DurationSeconds: seconds, Text: text[start:end]})
+	}
+	return timers
+}
+
+// recipeTimers lists r's timers. A structured step with durationSeconds
+// gives its timer directly; other steps are searched for durations.
+func recipeTimers(r Recipe) []Timer {
+	timers := make([]Timer, 0)
+	for n, text := range r.Instructions {
+		if n < len(r.Steps) && r.Steps[n].DurationSeconds > 0 {
+			timers = append(timers, Timer{Step: n + 1, DurationSeconds: r.Steps[n].DurationSeconds, Text: r.Steps[n].Text})
+			continue
+		}
+		timers = append(timers, extractTimers(n+1, text)...)
+	}
+	return timers
+}
+
+// TimersHandler lists the timers that can be derived from a recipe's
+// instructions. Steps without a duration are omitted.
+//
+// @Summary Extract cooking timers
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {array} Timer
+// @Failure 404 {object} ErrorResponse
+// @Router /recipe/{id}/timers [get]
+func TimersHandler(c *gin.Context) {
+	recipesMu.RLock()
+	i := findRecipe(c.Param("id"))
+	if i < 0 {
+		recipesMu.RUnlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	timers := recipeTimers(recipes[i])
+	recipesMu.RUnlock()
+	c.JSON(http.StatusOK, timers)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestTimersFromInstructions(t *testing.T) {
+	r := testRecipe("r1", "Roast")
+	r.Instructions = []string{
+		"Preheat the oven.",
+		"Bake for 25 minutes.",
+		"Roast for 1 hour 15 minutes, then rest 10 mins.",
+		"Simmer for 1.5 hours and 30 seconds.",
+	}
+	router := newTestRouter(t, nil, r)
+
+	w := serve(router, http.MethodGet, "/recipe/r1/timers", "")
+	expectStatus(t, w, http.StatusOK)
+	want := []Timer{
+		{Step: 2, DurationSeconds: 25 * 60, Text: "25 minutes"},
+		{Step: 3, DurationSeconds: 75 * 60, Text: "1 hour 15 minutes"},
+		{Step: 3, DurationSeconds: 10 * 60, Text: "10 mins"},
+		{Step: 4, DurationSeconds: 90*60 + 30, Text: "1.5 hours and 30 seconds"},
+	}
+	if got := decodeBody[[]Timer](t, w); !slices.Equal(got, want) {
+		t.Errorf("timers = %+v, want %+v", got, want)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/recipe/missing/timers", ""), http.StatusNotFound)
+}
@@ -0,0 +1,27 @@
+package main
+
+import "time"
+
+// Timestamp is a time.Time that serializes as null when zero, so a recipe
+// loaded without a timestamp does not show up as published in the year 1.
+// Decoding accepts null or an RFC 3339 string, as time.Time does.
+type Timestamp struct {
+	time.Time
+}
+
+// MarshalJSON writes null for the zero time and an RFC 3339 string
+// otherwise.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return t.Time.MarshalJSON()
+}
+
+// rfc3339 formats t in UTC for text outputs such as CSV, or "" when zero.
+func (t Timestamp) rfc3339() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestZeroTimestampsAreNull(t *testing.T) {
+	dated := testRecipe("dated", "Stew")
+	dated.PublishedAt = Timestamp{time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+	dated.UpdatedAt = dated.PublishedAt
+	router := newTestRouter(t, nil, testRecipe("undated", "Soup"), dated)
+
+	w := serve(router, http.MethodGet, "/recipe/undated", "")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[map[string]json.RawMessage](t, w)
+	for _, field := range []string{"publishedAt", "updatedAt"} {
+		if string(got[field]) != "null" {
+			t.Errorf("%s = %s, want null", field, got[field])
+		}
+	}
+	if strings.Contains(w.Body.String(), "0001-01-01") {
+		t.Errorf("body %s carries a year-1 timestamp", w.Body.String())
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/dated", "")
+	expectStatus(t, w, http.StatusOK)
+	got = decodeBody[map[string]json.RawMessage](t, w)
+	if string(got["publishedAt"]) != `"2024-03-01T12:00:00Z"` {
+		t.Errorf("publishedAt = %s, want the RFC 3339 time", got["publishedAt"])
+	}
+}
+
+func TestTimestampDecodesNull(t *testing.T) {
+	var r struct {
+		PublishedAt Timestamp `json:"publishedAt"`
+		UpdatedAt   Timestamp `json:"updatedAt"`
+	}
+	if err := json.Unmarshal([]byte(`{"publishedAt":null,"updatedAt":"2024-03-01T12:00:00Z"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.PublishedAt.IsZero() || !r.UpdatedAt.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("decoded %+v", r)
+	}
+}
+
+func TestZeroTimestampsInTextOutputs(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("undated", "Soup"))
+
+	w := serve(router, http.MethodGet, "/recipes/export.csv", "")
+	expectStatus(t, w, http.StatusOK)
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("export = %q, %v; want the header and one row", rows, err)
+	}
+	for _, col := range []string{"publishedAt", "updatedAt"} {
+		i := slices.Index(rows[0], col)
+		if i < 0 || rows[1][i] != "" {
+			t.Errorf("CSV %s column = %q, want empty", col, rows[1][max(i, 0)])
+		}
+	}
+
+	feed, _ := feedTitles(t, router, "/recipes/feed.atom")
+	if len(feed.Entries) != 1 || feed.Entries[0].Published != "" {
+		t.Errorf("feed entries = %+v, want one without <published>", feed.Entries)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Translation holds a recipe's text in another language. Fields left empty
+// fall back to the recipe's base fields.
+type Translation struct {
+	Name         string   `json:"name,omitempty"`
+	Ingredients  []string `json:"ingredients,omitempty"`
+	Instructions []string `json:"instructions,omitempty"`
+}
+
+// cloneTranslations returns a copy of translations that shares no map or
+// slices with it.
+func cloneTranslations(translations map[string]Translation) map[string]Translation {
+	if translations == nil {
+		return nil
+	}
+	clone := make(map[string]Translation, len(translations))
+	for lang, t := range translations {
+		t.Ingredients = slices.Clone(t.Ingredients)
+		t.Instructions = slices.Clone(t.Instructions)
+		clone[lang] = t
+	}
+	return clone
+}
+
+// normalizeTranslations rewrites r's language and translation keys as
+// canonical BCP 47 tags, so "EN-gb" and "en-GB" are the same translation.
+// Keys that do not parse are left for validateTranslations to reject.
+func normalizeTranslations(r *Recipe) {
+	if tag, err := language.Parse(r.Language); err == nil {
+		r.Language = tag.String()
+	}
+	if len(r.Translations) == 0 {
+		return
+	}
+	out := make(map[string]Translation, len(r.Translations))
+	for k, t := range r.Translations {
+		if tag, err := language.Parse(k); err == nil {
+			k = tag.String()
+		}
+		out[k] = t
+	}
+	r.Translations = out
+}
+
+// validateTranslations reports a language or translation key that is not a
+// BCP 47 tag.
+func validateTranslations(r *Recipe) error {
+	if r.Language != "" {
+		if _, err := language.Parse(r.Language); err != nil {
+			return fmt.Errorf("language %q is not a BCP 47 language tag", r.Language)
+		}
+	}
+	for k := range r.Translations {
+		if _, err := language.Parse(k); err != nil {
+			return fmt.Errorf("translations: %q is not a BCP 47 language tag", k)
+		}
+	}
+	return nil
+}
+
+// localizeRecipe returns r in the language best matching an Accept-Language
+// header, such as "es;q=0.9, en;q=0.8". The base fields count as r.Language
+// (undetermined when unset), so a client preferring it keeps them; when no
+// translation matches any preference the base fields are kept too. The
+// chosen translation replaces only the fields it sets, and Language reports
+// the language picked.
+func localizeRecipe(r Recipe, acceptLanguage string) Recipe {
+	if len(r.Translations) == 0 || acceptLanguage == "" {
+		return r
+	}
+	tags, weights, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return r
+	}
+	prefs := make([]language.Tag, 0, len(tags))
+	for i, t := range tags {
+		if weights[i] > 0 {
+			prefs = append(prefs, t)
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(r.Translations))
+	supported := make([]language.Tag, 0, 1+len(keys))
+	supported = append(supported, language.Make(r.Language))
+	for _, k := range keys {
+		supported = append(supported, language.Make(k))
+	}
+	_, i, confidence := language.NewMatcher(supported).Match(prefs...)
+	if confidence == language.No || i == 0 {
+		return r
+	}
+
+	t := r.Translations[keys[i-1]]
+	if t.Name != "" {
+		r.Name = t.Name
+	}
+	if len(t.Ingredients) > 0 {
+		r.Ingredients = t.Ingredients
+	}
+	if len(t.Instructions) > 0 {
+		r.Instructions = t.Instructions
+	}
+	r.Language = keys[i-1]
+	return r
+}
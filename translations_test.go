@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func translatedRecipe() Recipe {
+	r := testRecipe("r1", "Tomato soup")
+	r.Language = "en"
+	r.Ingredients = []string{"4 tomatoes"}
+	r.Instructions = []string{"Simmer."}
+	r.Translations = map[string]Translation{
+		"es":    {Name: "Sopa de tomate", Ingredients: []string{"4 tomates"}},
+		"fr":    {Name: "Soupe de tomates", Instructions: []string{"Laisser mijoter."}},
+		"pt-BR": {Name: "Sopa de tomate brasileira"},
+	}
+	return r
+}
+
+func TestAcceptLanguageNegotiation(t *testing.T) {
+	router := newTestRouter(t, nil, translatedRecipe())
+
+	for _, tc := range []struct {
+		accept, lang, name string
+	}{
+		{"", "en", "Tomato soup"},
+		{"es", "es", "Sopa de tomate"},
+		{"es;q=0.9, fr;q=0.95", "fr", "Soupe de tomates"},
+		{"de, fr;q=0.5, es;q=0.4", "fr", "Soupe de tomates"},
+		{"de, ja;q=0.8", "en", "Tomato soup"},
+		{"en;q=0.9, es;q=0.8", "en", "Tomato soup"},
+		{"es;q=0, fr;q=0.1", "fr", "Soupe de tomates"},
+		{"fr-CA", "fr", "Soupe de tomates"},
+		{"pt-BR, es;q=0.5", "pt-BR", "Sopa de tomate brasileira"},
+		{"not a language!!", "en", "Tomato soup"},
+	} {
+		w := serve(router, http.MethodGet, "/recipe/r1", "", "Accept-Language", tc.accept)
+		expectStatus(t, w, http.StatusOK)
+		got := decodeBody[Recipe](t, w)
+		if got.Language != tc.lang || got.Name != tc.name {
+			t.Errorf("Accept-Language %q: got %s %q, want %s %q", tc.accept, got.Language, got.Name, tc.lang, tc.name)
+		}
+		if cl := w.Header().Get("Content-Language"); cl != tc.lang {
+			t.Errorf("Accept-Language %q: Content-Language = %q, want %q", tc.accept, cl, tc.lang)
+		}
+		if !slices.Contains(w.Header().Values("Vary"), "Accept-Language") {
+			t.Errorf("Accept-Language %q: Vary = %q", tc.accept, w.Header().Values("Vary"))
+		}
+	}
+}
+
+func TestTranslationFallsBackPerField(t *testing.T) {
+	router := newTestRouter(t, nil, translatedRecipe())
+
+	w := serve(router, http.MethodGet, "/recipe/r1", "", "Accept-Language", "es")
+	expectStatus(t, w, http.StatusOK)
+	got := decodeBody[Recipe](t, w)
+	if !slices.Equal(got.Ingredients, []string{"4 tomates"}) || !slices.Equal(got.Instructions, []string{"Simmer."}) {
+		t.Errorf("es: ingredients %q instructions %q, want translated ingredients and base instructions", got.Ingredients, got.Instructions)
+	}
+
+	w = serve(router, http.MethodGet, "/recipe/r1", "", "Accept-Language", "fr")
+	got = decodeBody[Recipe](t, w)
+	if !slices.Equal(got.Ingredients, []string{"4 tomatoes"}) || !slices.Equal(got.Instructions, []string{"Laisser mijoter."}) {
+		t.Errorf("fr: ingredients %q instructions %q, want base ingredients and translated instructions", got.Ingredients, got.Instructions)
+	}
+}
+
+func TestTranslationKeysValidated(t *testing.T) {
+	router := newTestRouter(t, nil)
+
+	w := serve(router, http.MethodPost, "/recipes",
+		`{"name":"Soup","language":"EN","ingredients":["water"],"instructions":["Boil."],"translations":{"ES-mx":{"name":"Sopa"}}}`)
+	expectStatus(t, w, http.StatusCreated)
+	got := decodeBody[Recipe](t, w)
+	if _, ok := got.Translations["es-MX"]; !ok || got.Language != "en" {
+		t.Errorf("language %q translations %v, want canonical tags", got.Language, got.Translations)
+	}
+	expectStatus(t, serve(router, http.MethodPost, "/recipes",
+		`{"name":"Soup","ingredients":["water"],"instructions":["Boil."],"translations":{"not a tag":{"name":"?"}}}`), http.StatusUnprocessableEntity)
+}
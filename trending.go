@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	viewBucketSize = 5 * time.Minute
+	viewRetention  = 7 * 24 * time.Hour
+)
+
+// viewCounter counts recipe views in fixed-size time buckets so that counts
+// for a rolling window can be summed and old views age out.
+type viewCounter struct {
+	mu      sync.Mutex
+	size    time.Duration
+	keep    time.Duration
+	buckets map[int64]map[string]int
+}
+
+func newViewCounter(size, keep time.Duration) *viewCounter {
+	return &viewCounter{size: size, keep: keep, buckets: make(map[int64]map[string]int)}
+}
+
+var recipeViews = newViewCounter(viewBucketSize, viewRetention)
+
+func (v *viewCounter) bucket(t time.Time) int64 {
+	return t.UnixNano() / int64(v.size)
+}
+
+// record counts one view of id at now and drops buckets past retention.
+func (v *viewCounter) record(id string, now time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	b := v.bucket(now)
+	counts := v.buckets[b]
+	if counts == nil {
+		counts = make(map[string]int)
+		v.buckets[b] = counts
+	}
+	counts[id]++
+
+	oldest := v.bucket(now.Add(-v.keep))
+	for k := range v.buckets {
+		if k < oldest {
+			delete(v.buckets, k)
+		}
+	}
+}
+
+// counts sums views per recipe over the buckets overlapping the window
+// ending at now.
+func (v *viewCounter) counts(window time.Duration, now time.Time) map[string]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	from := v.bucket(now.Add(-window))
+	to := v.bucket(now)
+	total := make(map[string]int)
+	for k, counts := range v.buckets {
+		if k < from || k > to {
+			continue
+		}
+		for id, n := range counts {
+			total[id] += n
+		}
+	}
+	return total
+}
+
+// TrendingRecipe is a recipe with its view count in the requested window.
+type TrendingRecipe struct {
+	Recipe
+	Views int `json:"views"`
+}
+
+// TrendingRecipesHandler returns the most viewed recipes within ?window=
+// (a Go duration, default 24h, at most 7 days), most viewed first.
+//
+// @Summary List trending recipes
+// @Tags recipes
+// @Produce json
+// @Param window query string false "Rolling window, e.g. 1h or 24h (default 24h)"
+// @Param limit query int false "Maximum results (default 10)"
+// @Success 200 {array} TrendingRecipe
+// @Failure 400 {object} ErrorResponse
+// @Router /recipes/trending [get]
+func TrendingRecipesHandler(c *gin.Context) {
+	window := 24 * time.Hour
+	if v := c.Query("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 || d > viewRetention {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive duration of at most 168h"})
+			return
+		}
+		window = d
+	}
+	limit := 10
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = min(n, maxPageLimit)
+	}
+
+	counts := recipeViews.counts(window, time.Now())
+
+	recipesMu.RLock()
+	out := make([]TrendingRecipe, 0, len(counts))
+	for id, n := range counts {
+		if i := findRecipe(id); i >= 0 {
+			out = append(out, TrendingRecipe{Recipe: fresh(recipes[i]), Views: n})
+		}
+	}
+	recipesMu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Views != out[j].Views {
+			return out[i].Views > out[j].Views
+		}
+		return out[i].PublishedAt.After(out[j].PublishedAt.Time)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	c.JSON(http.StatusOK, out)
+}
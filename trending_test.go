@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func trendingViews(t *testing.T, router http.Handler, target string) ([]string, []int) {
+	t.Helper()
+	w := serve(router, http.MethodGet, target, "")
+	expectStatus(t, w, http.StatusOK)
+	var ids []string
+	var views []int
+	for _, r := range decodeBody[[]TrendingRecipe](t, w) {
+		ids = append(ids, r.ID)
+		views = append(views, r.Views)
+	}
+	return ids, views
+}
+
+func TestTrendingOrder(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("a", "A"), testRecipe("b", "B"), testRecipe("c", "C"))
+	for _, id := range []string{"b", "a", "b", "c", "b", "a"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipe/"+id, ""), http.StatusOK)
+	}
+
+	ids, views := trendingViews(t, router, "/recipes/trending?limit=2")
+	if len(ids) != 2 || ids[0] != "b" || ids[1] != "a" || views[0] != 3 || views[1] != 2 {
+		t.Errorf("trending = %q with views %v, want [b a] with [3 2]", ids, views)
+	}
+}
+
+func TestTrendingWindow(t *testing.T) {
+	router := newTestRouter(t, nil, testRecipe("old", "Old"), testRecipe("new", "New"))
+	now := time.Now()
+	for range 3 {
+		recipeViews.record("old", now.Add(-3*time.Hour))
+	}
+	recipeViews.record("new", now)
+
+	ids, _ := trendingViews(t, router, "/recipes/trending?window=1h")
+	if len(ids) != 1 || ids[0] != "new" {
+		t.Errorf("1h window = %q, want [new]", ids)
+	}
+	ids, views := trendingViews(t, router, "/recipes/trending?window=24h")
+	if len(ids) != 2 || ids[0] != "old" || views[0] != 3 {
+		t.Errorf("24h window = %q with views %v, want old first with 3 views", ids, views)
+	}
+
+	recipeViews.record("new", now.Add(viewRetention+time.Hour))
+	if got := recipeViews.counts(viewRetention, now.Add(viewRetention+time.Hour)); got["old"] != 0 {
+		t.Errorf("views past retention were kept: %v", got)
+	}
+}
+
+func TestTrendingRejectsBadWindow(t *testing.T) {
+	router := newTestRouter(t, nil)
+	for _, q := range []string{"window=soon", "window=-1h", "window=200h", "limit=0"} {
+		expectStatus(t, serve(router, http.MethodGet, "/recipes/trending?"+q, ""), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WarmupStep is the outcome of one warm-up task.
+type WarmupStep struct {
+	Name  string `json:"name"`
+	Took  string `json:"took"`
+	Error string `json:"error,omitempty"`
+}
+
+// WarmupResponse is the body of POST /admin/warmup.
+type WarmupResponse struct {
+	Ready bool         `json:"ready"`
+	Steps []WarmupStep `json:"steps"`
+	Took  string       `json:"took"`
+}
+
+// timeStep runs fn and records how long it took and whether it failed.
+func timeStep(name string, fn func() error) WarmupStep {
+	start := time.Now()
+	err := fn()
+	step := WarmupStep{Name: name, Took: time.Since(start).String()}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	return step
+}
+
+// WarmupHandler rebuilds the search index, fills the list cache and checks
+// the configured audit log, so an orchestrator can hold traffic until the
+// instance is warm. It answers 503 if any step failed.
+//
+// @Summary Warm up the index and caches
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} WarmupResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 503 {object} WarmupResponse
+// @Router /admin/warmup [post]
+func WarmupHandler(c *gin.Context) {
+	start := time.Now()
+	steps := []WarmupStep{
+		timeStep("searchIndex", func() error {
+			recipesMu.Lock()
+			defer recipesMu.Unlock()
+			searchIndex.rebuild(recipes)
+			return nil
+		}),
+		timeStep("listCache", func() error {
+			_, err := cachedRecipeList()
+			return err
+		}),
+		timeStep("auditLog", auditor.ping),
+	}
+
+	resp := WarmupResponse{Ready: true, Steps: steps}
+	for _, s := range steps {
+		if s.Error != "" {
+			resp.Ready = false
+		}
+	}
+	resp.Took = time.Since(start).String()
+	status := http.StatusOK
+	if !resp.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
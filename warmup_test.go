@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWarmupFillsCaches(t *testing.T) {
+	router := newTestRouter(t, withUsers, testRecipe("r1", "Tofu stir fry", "vegan"))
+	recipesMu.Lock()
+	searchIndex = newRecipeIndex()
+	listCache = nil
+	recipesMu.Unlock()
+
+	expectStatus(t, serve(router, http.MethodPost, "/admin/warmup", ""), http.StatusUnauthorized)
+
+	w := serve(router, http.MethodPost, "/admin/warmup", "", "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusOK)
+	resp := decodeBody[WarmupResponse](t, w)
+	if !resp.Ready {
+		t.Errorf("got %+v, want ready", resp)
+	}
+	var names []string
+	for _, s := range resp.Steps {
+		names = append(names, s.Name)
+		if _, err := time.ParseDuration(s.Took); err != nil || s.Error != "" {
+			t.Errorf("step %+v, want a duration and no error", s)
+		}
+	}
+	if want := []string{"searchIndex", "listCache", "auditLog"}; !slices.Equal(names, want) {
+		t.Errorf("steps = %q, want %q", names, want)
+	}
+	if _, err := time.ParseDuration(resp.Took); err != nil {
+		t.Errorf("took = %q: %v", resp.Took, err)
+	}
+
+	recipesMu.RLock()
+	defer recipesMu.RUnlock()
+	if _, ok := searchIndex.all["r1"]; !ok {
+		t.Error("search index not rebuilt")
+	}
+	if !listCacheValid(clock()) {
+		t.Error("list cache not filled")
+	}
+}
+
+func TestWarmupReportsFailedStep(t *testing.T) {
+	router := newTestRouter(t, func(cfg *Config) {
+		withUsers(cfg)
+		cfg.AuditLog = filepath.Join(t.TempDir(), "missing", "audit.log")
+	})
+
+	w := serve(router, http.MethodPost, "/admin/warmup", "", "X-API-KEY", "alice-key")
+	expectStatus(t, w, http.StatusServiceUnavailable)
+	resp := decodeBody[WarmupResponse](t, w)
+	if resp.Ready || resp.Steps[2].Name != "auditLog" || resp.Steps[2].Error == "" {
+		t.Errorf("got %+v, want not ready with the audit log step failed", resp)
+	}
+}